@@ -0,0 +1,76 @@
+package apitest
+
+import "encoding/hex"
+import "licensezero.com/cli/api"
+import "net/http"
+import "sort"
+import "time"
+
+func (server *Server) register(parsed *request) (map[string]interface{}, *responseError) {
+	if parsed.Name == "" {
+		return nil, failure(http.StatusBadRequest, "missing name")
+	}
+	if parsed.Jurisdiction == "" {
+		return nil, failure(http.StatusBadRequest, "missing jurisdiction")
+	}
+	if parsed.EMail == "" {
+		return nil, failure(http.StatusBadRequest, "missing email")
+	}
+	if parsed.Terms != "I agree to "+api.TermsReference+"." {
+		return nil, failure(http.StatusBadRequest, "must agree to terms of service")
+	}
+	server.registrations = append(server.registrations, Registration{
+		Name:         parsed.Name,
+		Jurisdiction: parsed.Jurisdiction,
+		EMail:        parsed.EMail,
+	})
+	return map[string]interface{}{}, nil
+}
+
+func (server *Server) reset(parsed *request) (map[string]interface{}, *responseError) {
+	developer, ok := server.developers[parsed.DeveloperID]
+	if !ok {
+		return nil, noSuchDeveloper
+	}
+	if developer.EMail != parsed.EMail {
+		return nil, failure(http.StatusBadRequest, "e-mail does not match")
+	}
+	developer.Resets++
+	return map[string]interface{}{}, nil
+}
+
+func (server *Server) developer(parsed *request) (map[string]interface{}, *responseError) {
+	developer, ok := server.developers[parsed.DeveloperID]
+	if !ok {
+		return nil, noSuchDeveloper
+	}
+	var offers []*Offer
+	for _, offer := range server.offers {
+		if offer.DeveloperID == developer.DeveloperID {
+			offers = append(offers, offer)
+		}
+	}
+	sort.Slice(offers, func(i, j int) bool {
+		if offers[i].Offered.Equal(offers[j].Offered) {
+			return offers[i].OfferID < offers[j].OfferID
+		}
+		return offers[i].Offered.Before(offers[j].Offered)
+	})
+	list := []api.OfferInformation{}
+	for _, offer := range offers {
+		information := api.OfferInformation{
+			OfferID: offer.OfferID,
+			Offered: offer.Offered.Format(time.RFC3339),
+		}
+		if !offer.Retracted.IsZero() {
+			information.Retracted = offer.Retracted.Format(time.RFC3339)
+		}
+		list = append(list, information)
+	}
+	return map[string]interface{}{
+		"name":         developer.Name,
+		"jurisdiction": developer.Jurisdiction,
+		"publicKey":    hex.EncodeToString(developer.PublicKey),
+		"offers":       list,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package apitest
+
+import "crypto/rand"
+import "encoding/hex"
+import "encoding/json"
+import "golang.org/x/crypto/ed25519"
+import "licensezero.com/cli/data"
+import "net/http"
+import "time"
+
+const waiverText = "The licensor waives the condition of the public license " +
+	"that would otherwise require the beneficiary to buy a private license, " +
+	"for the term stated above."
+
+func (server *Server) freebie(parsed *request) (map[string]interface{}, *responseError) {
+	developer, failed := server.authenticate(parsed)
+	if failed != nil {
+		return nil, failed
+	}
+	offer, failed := server.ownOffer(developer, parsed.OfferID)
+	if failed != nil {
+		return nil, failed
+	}
+	if !offer.Retracted.IsZero() {
+		return nil, failure(http.StatusBadRequest, "offer retracted")
+	}
+	if parsed.Name == "" || parsed.Jurisdiction == "" || parsed.EMail == "" {
+		return nil, failure(http.StatusBadRequest, "missing beneficiary information")
+	}
+	if !validTerm(parsed.Term) {
+		return nil, failure(http.StatusBadRequest, "invalid term")
+	}
+	manifest, err := json.Marshal(data.WaiverManifest{
+		Form:    "waiver",
+		Version: "1.0.0",
+		Date:    time.Now().UTC().Format(time.RFC3339),
+		Term:    parsed.Term,
+		Beneficiary: data.Party{
+			Name:         parsed.Name,
+			Jurisdiction: parsed.Jurisdiction,
+			EMail:        parsed.EMail,
+		},
+		Licensor: data.Party{
+			Name:         developer.Name,
+			Jurisdiction: developer.Jurisdiction,
+			PublicKey:    hex.EncodeToString(developer.PublicKey),
+		},
+		Offer: data.OfferReference{
+			OfferID:     offer.OfferID,
+			Homepage:    offer.Homepage,
+			Description: offer.Description,
+		},
+	})
+	if err != nil {
+		return nil, failure(http.StatusInternalServerError, "internal error")
+	}
+	document := string(manifest) + "\n\n" + waiverText + "\n"
+	signingKey := developer.privateKey
+	if server.forgeWaivers {
+		_, signingKey, _ = ed25519.GenerateKey(rand.Reader)
+	}
+	return map[string]interface{}{
+		"manifest":       string(manifest),
+		"document":       document,
+		"publicKey":      hex.EncodeToString(developer.PublicKey),
+		"signature":      hex.EncodeToString(ed25519.Sign(signingKey, []byte(document))),
+		"agentSignature": hex.EncodeToString(ed25519.Sign(server.agentPrivateKey, []byte(document))),
+		"issued":         time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func validTerm(term interface{}) bool {
+	switch value := term.(type) {
+	case string:
+		return value == "forever"
+	case float64:
+		return value >= 1 && value == float64(uint(value))
+	default:
+		return false
+	}
+}
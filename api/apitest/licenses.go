@@ -0,0 +1,51 @@
+package apitest
+
+import "encoding/hex"
+import "encoding/json"
+import "errors"
+import "golang.org/x/crypto/ed25519"
+import "licensezero.com/cli/data"
+import "time"
+
+const licenseText = "The licensor grants the licensee a private license " +
+	"to use the software under the terms stated above."
+
+// IssueLicense signs a private license for an offer,
+// as if the licensee had bought one through licensezero.com.
+func (server *Server) IssueLicense(offerID string, licensee data.Party) (*data.License, error) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	offer, ok := server.offers[offerID]
+	if !ok {
+		return nil, errors.New("no such offer")
+	}
+	developer := server.developers[offer.DeveloperID]
+	manifest, err := json.Marshal(data.LicenseManifest{
+		Form:     data.LicenseForm,
+		Version:  "1.0.0",
+		Date:     time.Now().UTC().Format(time.RFC3339),
+		Licensee: licensee,
+		Licensor: data.Party{
+			Name:         developer.Name,
+			Jurisdiction: developer.Jurisdiction,
+			PublicKey:    hex.EncodeToString(developer.PublicKey),
+		},
+		Offer: data.OfferReference{
+			OfferID:     offer.OfferID,
+			Homepage:    offer.Homepage,
+			Description: offer.Description,
+		},
+		Price: offer.Pricing.Private,
+	})
+	if err != nil {
+		return nil, err
+	}
+	document := string(manifest) + "\n\n" + licenseText + "\n"
+	return &data.License{
+		Manifest:       string(manifest),
+		Document:       document,
+		PublicKey:      hex.EncodeToString(developer.PublicKey),
+		Signature:      hex.EncodeToString(ed25519.Sign(developer.privateKey, []byte(document))),
+		AgentSignature: hex.EncodeToString(ed25519.Sign(server.agentPrivateKey, []byte(document))),
+	}, nil
+}
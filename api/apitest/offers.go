@@ -0,0 +1,174 @@
+package apitest
+
+import "encoding/hex"
+import "licensezero.com/cli/api"
+import "net/http"
+import "net/url"
+import "time"
+
+func (server *Server) offer(parsed *request) (map[string]interface{}, *responseError) {
+	developer, failed := server.authenticate(parsed)
+	if failed != nil {
+		return nil, failed
+	}
+	if parsed.Terms != "I agree to "+api.AgencyReference+"." {
+		return nil, failure(http.StatusBadRequest, "must agree to agency terms")
+	}
+	if !validHomepage(parsed.Homepage) {
+		return nil, failure(http.StatusBadRequest, "invalid homepage")
+	}
+	if parsed.Description == "" {
+		return nil, failure(http.StatusBadRequest, "missing description")
+	}
+	if parsed.Pricing == nil || parsed.Pricing.Private == 0 {
+		return nil, failure(http.StatusBadRequest, "invalid pricing")
+	}
+	offer := &Offer{
+		OfferID:     newID(),
+		DeveloperID: developer.DeveloperID,
+		Homepage:    parsed.Homepage,
+		Description: parsed.Description,
+		Pricing:     *parsed.Pricing,
+		Commission:  DefaultCommission,
+		Offered:     time.Now().UTC(),
+	}
+	server.offers[offer.OfferID] = offer
+	return map[string]interface{}{"offerID": offer.OfferID}, nil
+}
+
+func (server *Server) offering(parsed *request) (map[string]interface{}, *responseError) {
+	if status, broken := server.brokenOfferings[parsed.OfferID]; broken {
+		return nil, failure(status, http.StatusText(status))
+	}
+	offer, ok := server.offers[parsed.OfferID]
+	if !ok {
+		return nil, noSuchOffer
+	}
+	developer := server.developers[offer.DeveloperID]
+	response := map[string]interface{}{
+		"developer": map[string]interface{}{
+			"name":         developer.Name,
+			"jurisdiction": developer.Jurisdiction,
+			"publicKey":    hex.EncodeToString(developer.PublicKey),
+		},
+		"pricing":     offer.Pricing,
+		"homepage":    offer.Homepage,
+		"description": offer.Description,
+		"lock":        offer.Lock,
+		"commission":  offer.Commission,
+	}
+	if !offer.Retracted.IsZero() {
+		response["retracted"] = offer.Retracted.Format(time.RFC3339)
+	}
+	return response, nil
+}
+
+func (server *Server) reprice(parsed *request) (map[string]interface{}, *responseError) {
+	developer, failed := server.authenticate(parsed)
+	if failed != nil {
+		return nil, failed
+	}
+	offer, failed := server.ownOffer(developer, parsed.OfferID)
+	if failed != nil {
+		return nil, failed
+	}
+	if !offer.Retracted.IsZero() {
+		return nil, failure(http.StatusBadRequest, "offer retracted")
+	}
+	if parsed.Pricing == nil || parsed.Pricing.Private == 0 {
+		return nil, failure(http.StatusBadRequest, "invalid pricing")
+	}
+	if locked(offer) && parsed.Pricing.Private > offer.Lock.Price {
+		return nil, failure(http.StatusBadRequest, "locked")
+	}
+	offer.Pricing = *parsed.Pricing
+	return map[string]interface{}{}, nil
+}
+
+func (server *Server) lock(parsed *request) (map[string]interface{}, *responseError) {
+	developer, failed := server.authenticate(parsed)
+	if failed != nil {
+		return nil, failed
+	}
+	offer, failed := server.ownOffer(developer, parsed.OfferID)
+	if failed != nil {
+		return nil, failed
+	}
+	if !offer.Retracted.IsZero() {
+		return nil, failure(http.StatusBadRequest, "offer retracted")
+	}
+	unlock, err := time.Parse(time.RFC3339, parsed.Unlock)
+	if err != nil {
+		return nil, failure(http.StatusBadRequest, "invalid unlock date")
+	}
+	now := time.Now().UTC()
+	if !unlock.After(now) {
+		return nil, failure(http.StatusBadRequest, "unlock date must be in the future")
+	}
+	if locked(offer) {
+		existing, _ := time.Parse(time.RFC3339, offer.Lock.Unlock)
+		if unlock.Before(existing) {
+			return nil, failure(http.StatusBadRequest, "already locked until "+offer.Lock.Unlock)
+		}
+	}
+	offer.Lock = api.LockInformation{
+		Locked: now.Format(time.RFC3339),
+		Unlock: unlock.UTC().Format(time.RFC3339),
+		Price:  offer.Pricing.Private,
+	}
+	return map[string]interface{}{}, nil
+}
+
+func (server *Server) retract(parsed *request) (map[string]interface{}, *responseError) {
+	developer, failed := server.authenticate(parsed)
+	if failed != nil {
+		return nil, failed
+	}
+	offer, failed := server.ownOffer(developer, parsed.OfferID)
+	if failed != nil {
+		return nil, failed
+	}
+	if !offer.Retracted.IsZero() {
+		return nil, failure(http.StatusBadRequest, "already retracted")
+	}
+	if locked(offer) {
+		return nil, failure(http.StatusBadRequest, "locked until "+offer.Lock.Unlock)
+	}
+	offer.Retracted = time.Now().UTC()
+	return map[string]interface{}{}, nil
+}
+
+func (server *Server) raise(parsed *request) (map[string]interface{}, *responseError) {
+	developer, failed := server.authenticate(parsed)
+	if failed != nil {
+		return nil, failed
+	}
+	offer, failed := server.ownOffer(developer, parsed.OfferID)
+	if failed != nil {
+		return nil, failed
+	}
+	if parsed.Commission > 100 {
+		return nil, failure(http.StatusBadRequest, "invalid commission")
+	}
+	if parsed.Commission <= offer.Commission {
+		return nil, failure(http.StatusBadRequest, "commission can only be raised")
+	}
+	offer.Commission = parsed.Commission
+	return map[string]interface{}{}, nil
+}
+
+func locked(offer *Offer) bool {
+	if offer.Lock.Unlock == "" {
+		return false
+	}
+	unlock, err := time.Parse(time.RFC3339, offer.Lock.Unlock)
+	return err == nil && unlock.After(time.Now())
+}
+
+func validHomepage(homepage string) bool {
+	parsed, err := url.Parse(homepage)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
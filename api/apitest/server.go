@@ -0,0 +1,295 @@
+// Package apitest provides an in-memory License Zero API server for tests.
+package apitest
+
+import "crypto/rand"
+import "encoding/hex"
+import "encoding/json"
+import "fmt"
+import "golang.org/x/crypto/ed25519"
+import "licensezero.com/cli/api"
+import "net/http"
+import "net/http/httptest"
+import "sync"
+import "time"
+
+// Server is an in-memory License Zero API server.
+type Server struct {
+	*httptest.Server
+	AgentPublicKey  ed25519.PublicKey
+	agentPrivateKey ed25519.PrivateKey
+	mutex           sync.Mutex
+	developers      map[string]*Developer
+	offers          map[string]*Offer
+	registrations   []Registration
+	counts          map[string]int
+	failures        map[string][]int
+	brokenOfferings map[string]int
+	forgeWaivers    bool
+}
+
+// Developer describes a developer registered with a Server.
+type Developer struct {
+	DeveloperID  string
+	Token        string
+	Name         string
+	Jurisdiction string
+	EMail        string
+	PublicKey    ed25519.PublicKey
+	privateKey   ed25519.PrivateKey
+	Resets       int
+}
+
+// Offer describes an offer made through a Server.
+type Offer struct {
+	OfferID     string
+	DeveloperID string
+	Homepage    string
+	Description string
+	Pricing     api.Pricing
+	Commission  uint
+	Offered     time.Time
+	Retracted   time.Time
+	Lock        api.LockInformation
+}
+
+// Registration describes a register request received by a Server.
+type Registration struct {
+	Name         string
+	Jurisdiction string
+	EMail        string
+}
+
+// DefaultCommission is the agent's commission on new offers, in percent.
+const DefaultCommission = 10
+
+type request struct {
+	Action       string       `json:"action"`
+	DeveloperID  string       `json:"developerID"`
+	Token        string       `json:"token"`
+	OfferID      string       `json:"offerID"`
+	Name         string       `json:"name"`
+	Jurisdiction string       `json:"jurisdiction"`
+	EMail        string       `json:"email"`
+	Terms        string       `json:"terms"`
+	Homepage     string       `json:"homepage"`
+	Description  string       `json:"description"`
+	Pricing      *api.Pricing `json:"pricing"`
+	Unlock       string       `json:"unlock"`
+	Commission   uint         `json:"commission"`
+	Term         interface{}  `json:"term"`
+}
+
+// responseError is returned by action handlers to send an error response.
+type responseError struct {
+	status  int
+	message string
+}
+
+func (err *responseError) Error() string {
+	return err.message
+}
+
+func failure(status int, message string) *responseError {
+	return &responseError{status: status, message: message}
+}
+
+var accessDenied = failure(http.StatusUnauthorized, "access denied")
+var noSuchOffer = failure(http.StatusNotFound, "no such offer")
+var noSuchDeveloper = failure(http.StatusNotFound, "no such developer")
+
+type handler func(*Server, *request) (map[string]interface{}, *responseError)
+
+var handlers = map[string]handler{
+	"developer": (*Server).developer,
+	"freebie":   (*Server).freebie,
+	"lock":      (*Server).lock,
+	"offer":     (*Server).offer,
+	"offering":  (*Server).offering,
+	"raise":     (*Server).raise,
+	"register":  (*Server).register,
+	"reprice":   (*Server).reprice,
+	"reset":     (*Server).reset,
+	"retract":   (*Server).retract,
+}
+
+// NewServer starts and returns a new Server.
+// The caller should call Close when finished.
+func NewServer() *Server {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	server := &Server{
+		AgentPublicKey:  publicKey,
+		agentPrivateKey: privateKey,
+		developers:      make(map[string]*Developer),
+		offers:          make(map[string]*Offer),
+		counts:          make(map[string]int),
+		failures:        make(map[string][]int),
+		brokenOfferings: make(map[string]int),
+	}
+	server.Server = httptest.NewServer(server)
+	return server
+}
+
+// ServeHTTP handles API requests.
+func (server *Server) ServeHTTP(writer http.ResponseWriter, httpRequest *http.Request) {
+	if httpRequest.Method != "POST" {
+		respond(writer, http.StatusMethodNotAllowed, map[string]interface{}{"error": "POST only"})
+		return
+	}
+	var parsed request
+	err := json.NewDecoder(httpRequest.Body).Decode(&parsed)
+	if err != nil {
+		respond(writer, http.StatusBadRequest, map[string]interface{}{"error": "invalid JSON"})
+		return
+	}
+	handle, ok := handlers[parsed.Action]
+	if !ok {
+		respond(writer, http.StatusBadRequest, map[string]interface{}{"error": "invalid action"})
+		return
+	}
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.counts[parsed.Action]++
+	if pending := server.failures[parsed.Action]; len(pending) > 0 {
+		server.failures[parsed.Action] = pending[1:]
+		respond(writer, pending[0], map[string]interface{}{"error": http.StatusText(pending[0])})
+		return
+	}
+	response, failed := handle(server, &parsed)
+	if failed != nil {
+		respond(writer, failed.status, map[string]interface{}{"error": failed.message})
+		return
+	}
+	response["error"] = false
+	respond(writer, http.StatusOK, response)
+}
+
+func respond(writer http.ResponseWriter, status int, body map[string]interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(body)
+}
+
+// AddDeveloper registers a developer with an access token and signing key.
+func (server *Server) AddDeveloper(name, jurisdiction, email string) Developer {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	developer := &Developer{
+		DeveloperID:  newID(),
+		Token:        newToken(),
+		Name:         name,
+		Jurisdiction: jurisdiction,
+		EMail:        email,
+		PublicKey:    publicKey,
+		privateKey:   privateKey,
+	}
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.developers[developer.DeveloperID] = developer
+	return *developer
+}
+
+// Developer returns a copy of the developer with the given ID.
+func (server *Server) Developer(developerID string) (Developer, bool) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	developer, ok := server.developers[developerID]
+	if !ok {
+		return Developer{}, false
+	}
+	return *developer, true
+}
+
+// Offer returns a copy of the offer with the given ID.
+func (server *Server) Offer(offerID string) (Offer, bool) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	offer, ok := server.offers[offerID]
+	if !ok {
+		return Offer{}, false
+	}
+	return *offer, true
+}
+
+// Registrations returns the register requests received so far.
+func (server *Server) Registrations() []Registration {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]Registration(nil), server.registrations...)
+}
+
+// FailNext makes the server respond to the next times requests
+// for action with the given HTTP status, without acting on them.
+func (server *Server) FailNext(action string, times int, status int) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	for i := 0; i < times; i++ {
+		server.failures[action] = append(server.failures[action], status)
+	}
+}
+
+// BreakOffering makes the server respond to every offering request
+// for offerID with the given HTTP status.
+func (server *Server) BreakOffering(offerID string, status int) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.brokenOfferings[offerID] = status
+}
+
+// ForgeWaivers makes the server sign waivers with a key other than the developer's.
+func (server *Server) ForgeWaivers() {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.forgeWaivers = true
+}
+
+// Count returns the number of requests received for an action.
+func (server *Server) Count(action string) int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.counts[action]
+}
+
+func (server *Server) authenticate(parsed *request) (*Developer, *responseError) {
+	developer, ok := server.developers[parsed.DeveloperID]
+	if !ok || parsed.Token == "" || developer.Token != parsed.Token {
+		return nil, accessDenied
+	}
+	return developer, nil
+}
+
+func (server *Server) ownOffer(developer *Developer, offerID string) (*Offer, *responseError) {
+	offer, ok := server.offers[offerID]
+	if !ok {
+		return nil, noSuchOffer
+	}
+	if offer.DeveloperID != developer.DeveloperID {
+		return nil, failure(http.StatusForbidden, "not your offer")
+	}
+	return offer, nil
+}
+
+func newID() string {
+	bytes := make([]byte, 16)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		panic(err)
+	}
+	// Set version 4 and the RFC 4122 variant.
+	bytes[6] = (bytes[6] & 0x0f) | 0x40
+	bytes[8] = (bytes[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", bytes[0:4], bytes[4:6], bytes[6:8], bytes[8:10], bytes[10:])
+}
+
+func newToken() string {
+	bytes := make([]byte, 32)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(bytes)
+}
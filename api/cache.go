@@ -0,0 +1,62 @@
+package api
+
+import "context"
+import "encoding/json"
+import "net/http"
+import "time"
+
+// Cache stores responses to read-only API requests.
+type Cache interface {
+	Get(action, key string) ([]byte, time.Time, bool)
+	Put(action, key string, body []byte)
+	Delete(action, key string)
+}
+
+// CacheTTLs lists how long cached responses stay fresh, by action.
+// Actions not listed are never cached.
+var CacheTTLs = map[string]time.Duration{
+	"developer": 5 * time.Minute,
+	"offering":  15 * time.Minute,
+}
+
+// cachedCall is like call, but serves fresh responses from client.Cache,
+// and falls back to stale responses if the API cannot be reached.
+func (client *Client) cachedCall(ctx context.Context, action, key string, requestData, responseData interface{}) error {
+	ttl, cacheable := CacheTTLs[action]
+	if client.Cache == nil || !cacheable {
+		return client.call(ctx, action, requestData, responseData)
+	}
+	if !client.Refresh {
+		body, stored, ok := client.Cache.Get(action, key)
+		if ok && time.Since(stored) < ttl && json.Unmarshal(body, responseData) == nil {
+			client.logf("%s %s: cached %s", action, key, stored.Format(time.RFC3339))
+			return nil
+		}
+	}
+	body, err := client.send(ctx, action, requestData)
+	if err != nil {
+		if IsNetworkError(err) && !client.Refresh {
+			stale, stored, ok := client.Cache.Get(action, key)
+			if ok && json.Unmarshal(stale, responseData) == nil {
+				if client.OnStale != nil {
+					client.OnStale(action, key, stored)
+				}
+				return nil
+			}
+		}
+		return err
+	}
+	err = json.Unmarshal(body, responseData)
+	if err != nil {
+		return &Error{Action: action, StatusCode: http.StatusOK, Message: "invalid response", Err: err}
+	}
+	client.Cache.Put(action, key, body)
+	return nil
+}
+
+// invalidate drops a cached response made outdated by a change.
+func (client *Client) invalidate(action, key string) {
+	if client.Cache != nil {
+		client.Cache.Delete(action, key)
+	}
+}
@@ -0,0 +1,101 @@
+package api_test
+
+import "context"
+import "licensezero.com/cli/api/apitest"
+import "licensezero.com/cli/data"
+import "testing"
+import "time"
+
+type memoryCache map[string]memoryEntry
+
+type memoryEntry struct {
+	body   []byte
+	stored time.Time
+}
+
+func (cache memoryCache) Get(action, key string) ([]byte, time.Time, bool) {
+	entry, ok := cache[action+"/"+key]
+	return entry.body, entry.stored, ok
+}
+
+func (cache memoryCache) Put(action, key string, body []byte) {
+	cache[action+"/"+key] = memoryEntry{body: body, stored: time.Now()}
+}
+
+func (cache memoryCache) Delete(action, key string) {
+	delete(cache, action+"/"+key)
+}
+
+func TestCacheHit(t *testing.T) {
+	server := apitest.NewServer()
+	defer server.Close()
+	developer := server.AddDeveloper("Jane Dev", "US-CA", "jane@example.com")
+	client := testClient(server)
+	client.Cache = memoryCache{}
+	for i := 0; i < 2; i++ {
+		_, _, err := client.Developer(context.Background(), developer.DeveloperID)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if server.Count("developer") != 1 {
+		t.Error("did not use cache")
+	}
+	client.Refresh = true
+	client.Developer(context.Background(), developer.DeveloperID)
+	if server.Count("developer") != 2 {
+		t.Error("did not refresh")
+	}
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	server := apitest.NewServer()
+	defer server.Close()
+	developer := server.AddDeveloper("Jane Dev", "US-CA", "jane@example.com")
+	credentials := &data.Developer{DeveloperID: developer.DeveloperID, Token: developer.Token}
+	client := testClient(server)
+	client.Cache = memoryCache{}
+	offerID, err := client.Offer(context.Background(), credentials, "https://example.com", "test", 1000, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Offering(context.Background(), offerID)
+	err = client.Reprice(context.Background(), credentials, offerID, 2000, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offering, err := client.Offering(context.Background(), offerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offering.Pricing.Private != 2000 {
+		t.Error("served outdated pricing")
+	}
+}
+
+func TestCacheStale(t *testing.T) {
+	server := apitest.NewServer()
+	developer := server.AddDeveloper("Jane Dev", "US-CA", "jane@example.com")
+	cache := memoryCache{}
+	client := testClient(server)
+	client.Cache = cache
+	client.Developer(context.Background(), developer.DeveloperID)
+	server.Close()
+	entry := cache["developer/"+developer.DeveloperID]
+	entry.stored = entry.stored.Add(-time.Hour)
+	cache["developer/"+developer.DeveloperID] = entry
+	warned := false
+	client.OnStale = func(action, key string, stored time.Time) {
+		warned = true
+	}
+	information, _, err := client.Developer(context.Background(), developer.DeveloperID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if information.Name != "Jane Dev" {
+		t.Error("wrong name")
+	}
+	if !warned {
+		t.Error("did not warn")
+	}
+}
@@ -0,0 +1,184 @@
+package api
+
+import "bytes"
+import "context"
+import "encoding/json"
+import "errors"
+import "io/ioutil"
+import "log"
+import "math/rand"
+import "net"
+import "net/http"
+import "time"
+
+// DefaultBaseURL is the URL of the licensezero.com API.
+const DefaultBaseURL = "https://licensezero.com/api/v0"
+
+// DefaultTimeout limits each attempt at an API request.
+const DefaultTimeout = 30 * time.Second
+
+// Client sends requests to a License Zero API server.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	UserAgent  string
+	// Timeout limits each attempt at a request.  Zero means no limit.
+	Timeout time.Duration
+	// Retries is the number of times to retry a request after a transient failure.
+	Retries int
+	// RetryDelay is the delay before the first retry.  Later retries back off exponentially.
+	RetryDelay time.Duration
+	// Cache stores responses to read-only requests.  Nil disables caching.
+	Cache Cache
+	// Refresh ignores cached responses, but still caches new ones.
+	Refresh bool
+	// OnStale, if set, is called when a stale cached response is used
+	// because the API could not be reached.
+	OnStale func(action, key string, stored time.Time)
+	// Logger, if set, receives a line for each request attempt.
+	Logger *log.Logger
+	// LogBodies adds request and response bodies, with secrets redacted, to Logger.
+	LogBodies bool
+}
+
+// idempotentActions lists actions that are safe to send more than once.
+var idempotentActions = map[string]bool{
+	"developer": true,
+	"offering":  true,
+}
+
+// NewClient returns a Client for the API at baseURL.
+func NewClient(baseURL, userAgent string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{},
+		UserAgent:  userAgent,
+		Timeout:    DefaultTimeout,
+		Retries:    3,
+		RetryDelay: 500 * time.Millisecond,
+	}
+}
+
+func (client *Client) post(ctx context.Context, body []byte) (*http.Response, error) {
+	request, err := http.NewRequest("POST", client.BaseURL, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/json")
+	if client.UserAgent != "" {
+		request.Header.Set("User-Agent", client.UserAgent)
+	}
+	httpClient := client.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return httpClient.Do(request)
+}
+
+// send sends an API request and returns the body of a successful response,
+// retrying transient failures.  All failures are returned as *Error.
+func (client *Client) send(ctx context.Context, action string, requestData interface{}) ([]byte, error) {
+	body, err := json.Marshal(requestData)
+	if err != nil {
+		return nil, &Error{Action: action, Message: "could not encode request", Err: err}
+	}
+	client.logBody("request", action, body)
+	for attempt := 0; ; attempt++ {
+		responseBody, err := client.attempt(ctx, action, body)
+		if err == nil {
+			return responseBody, nil
+		}
+		if attempt >= client.Retries || ctx.Err() != nil || !retryable(action, err) {
+			return nil, err
+		}
+		delay := client.backoff(attempt)
+		client.logf("%s: retrying in %s", action, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, err
+		}
+	}
+}
+
+func (client *Client) attempt(ctx context.Context, action string, body []byte) ([]byte, error) {
+	if client.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, client.Timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	response, err := client.post(ctx, body)
+	if err != nil {
+		client.logf("%s %s: %s (%s)", action, client.BaseURL, err, time.Since(start))
+		return nil, &Error{Action: action, Err: err}
+	}
+	defer response.Body.Close()
+	responseBody, err := ioutil.ReadAll(response.Body)
+	client.logf("%s %s: %d (%s)", action, client.BaseURL, response.StatusCode, time.Since(start))
+	if err != nil {
+		return nil, &Error{Action: action, StatusCode: response.StatusCode, Message: "error reading response", Err: err}
+	}
+	client.logBody("response", action, responseBody)
+	var parsed struct {
+		Error interface{} `json:"error"`
+	}
+	parseError := json.Unmarshal(responseBody, &parsed)
+	if message, ok := parsed.Error.(string); ok && parseError == nil {
+		return nil, &Error{Action: action, StatusCode: response.StatusCode, Message: message}
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, &Error{Action: action, StatusCode: response.StatusCode}
+	}
+	if parseError != nil {
+		return nil, &Error{Action: action, StatusCode: response.StatusCode, Message: "invalid response", Err: parseError}
+	}
+	return responseBody, nil
+}
+
+// backoff returns the delay before a retry, with jitter.
+func (client *Client) backoff(attempt int) time.Duration {
+	delay := client.RetryDelay << uint(attempt)
+	if delay <= 0 {
+		return 0
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+// retryable reports whether a failed request may be sent again.
+// Idempotent actions are retried on network errors and server errors.
+// Other actions are retried only if the request never left the machine,
+// so the server cannot have acted on it.
+func retryable(action string, err error) bool {
+	apiError, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+	if idempotentActions[action] {
+		return IsNetworkError(err) ||
+			apiError.StatusCode >= 500 ||
+			apiError.StatusCode == http.StatusTooManyRequests
+	}
+	var opError *net.OpError
+	return IsNetworkError(err) && errors.As(apiError.Err, &opError) && opError.Op == "dial"
+}
+
+// call sends an API request and parses a successful response into responseData.
+func (client *Client) call(ctx context.Context, action string, requestData, responseData interface{}) error {
+	responseBody, err := client.send(ctx, action, requestData)
+	if err != nil {
+		return err
+	}
+	if responseData == nil {
+		return nil
+	}
+	err = json.Unmarshal(responseBody, responseData)
+	if err != nil {
+		return &Error{Action: action, StatusCode: http.StatusOK, Message: "invalid response", Err: err}
+	}
+	return nil
+}
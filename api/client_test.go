@@ -0,0 +1,80 @@
+package api_test
+
+import "context"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/api/apitest"
+import "licensezero.com/cli/data"
+import "net/http"
+import "net/http/httptest"
+import "testing"
+import "time"
+
+func testClient(server *apitest.Server) *api.Client {
+	client := api.NewClient(server.URL, "")
+	client.RetryDelay = time.Millisecond
+	return client
+}
+
+func TestRetryIdempotent(t *testing.T) {
+	server := apitest.NewServer()
+	defer server.Close()
+	developer := server.AddDeveloper("Jane Dev", "US-CA", "jane@example.com")
+	client := testClient(server)
+	server.FailNext("developer", 2, http.StatusServiceUnavailable)
+	information, _, err := client.Developer(context.Background(), developer.DeveloperID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if information.Name != "Jane Dev" {
+		t.Error("wrong name")
+	}
+	if server.Count("developer") != 3 {
+		t.Error("did not retry")
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	server := apitest.NewServer()
+	defer server.Close()
+	client := testClient(server)
+	client.Retries = 1
+	server.FailNext("offering", 5, http.StatusBadGateway)
+	_, err := client.Offering(context.Background(), "1f838e1d-c98f-44a3-a4e8-15267a0f0777")
+	if err == nil || err.(*api.Error).StatusCode != http.StatusBadGateway {
+		t.Error("did not return server error")
+	}
+	if server.Count("offering") != 2 {
+		t.Error("wrong number of attempts")
+	}
+}
+
+func TestNoRetryNonIdempotent(t *testing.T) {
+	server := apitest.NewServer()
+	defer server.Close()
+	developer := server.AddDeveloper("Jane Dev", "US-CA", "jane@example.com")
+	client := testClient(server)
+	server.FailNext("offer", 1, http.StatusServiceUnavailable)
+	_, err := client.Offer(context.Background(), &data.Developer{DeveloperID: developer.DeveloperID, Token: developer.Token}, "https://example.com", "test", 1000, 0)
+	if err == nil {
+		t.Error("did not fail")
+	}
+	if server.Count("offer") != 1 {
+		t.Error("retried offer")
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	done := make(chan bool)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+	client := api.NewClient(server.URL, "")
+	client.Timeout = 10 * time.Millisecond
+	client.Retries = 0
+	_, _, err := client.Developer(context.Background(), "1f838e1d-c98f-44a3-a4e8-15267a0f0777")
+	if !api.IsNetworkError(err) {
+		t.Error("did not time out")
+	}
+}
@@ -1,10 +1,6 @@
 package api
 
-import "bytes"
-import "encoding/json"
-import "errors"
-import "io/ioutil"
-import "net/http"
+import "context"
 
 type developerRequest struct {
 	Action      string `json:"action"`
@@ -19,7 +15,6 @@ type OfferInformation struct {
 }
 
 type developerResponse struct {
-	Error        interface{}        `json:"error"`
 	Name         string             `json:"name"`
 	Jurisdiction string             `json:"jurisdiction"`
 	PublicKey    string             `json:"publicKey"`
@@ -34,31 +29,15 @@ type DeveloperInformation struct {
 }
 
 // Developer sends a developer API request.
-func Developer(developerID string) (*DeveloperInformation, []OfferInformation, error) {
+func (client *Client) Developer(ctx context.Context, developerID string) (*DeveloperInformation, []OfferInformation, error) {
 	bodyData := developerRequest{
 		Action:      "developer",
 		DeveloperID: developerID,
 	}
-	body, err := json.Marshal(bodyData)
-	if err != nil {
-		return nil, nil, errors.New("error encoding developer request body")
-	}
-	response, err := http.Post("https://licensezero.com/api/v0", "application/json", bytes.NewBuffer(body))
-	if err != nil {
-		return nil, nil, errors.New("error sending developer request")
-	}
-	defer response.Body.Close()
-	responseBody, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return nil, nil, errors.New("error reading developer response body")
-	}
 	var parsed developerResponse
-	err = json.Unmarshal(responseBody, &parsed)
+	err := client.cachedCall(ctx, "developer", developerID, bodyData, &parsed)
 	if err != nil {
-		return nil, nil, errors.New("error parsing developer response body")
-	}
-	if message, ok := parsed.Error.(string); ok {
-		return nil, nil, errors.New(message)
+		return nil, nil, err
 	}
 	developer := DeveloperInformation{
 		Name:         parsed.Name,
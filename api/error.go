@@ -0,0 +1,71 @@
+package api
+
+import "strconv"
+import "strings"
+
+// Error describes a failed API request.
+type Error struct {
+	// Action is the API action requested, like "offer".
+	Action string
+	// StatusCode is the HTTP status of the response, or 0 if none was received.
+	StatusCode int
+	// Message is the error message sent by the server, if any.
+	Message string
+	// Err is the underlying error, if any.
+	Err error
+}
+
+func (err *Error) Error() string {
+	if err.Message != "" {
+		if err.Err != nil {
+			return err.Message + ": " + err.Err.Error()
+		}
+		return err.Message
+	}
+	if err.StatusCode != 0 && err.StatusCode != 200 {
+		return "server responded " + strconv.Itoa(err.StatusCode)
+	}
+	if err.Err != nil {
+		return "error sending " + err.Action + " request: " + err.Err.Error()
+	}
+	return "error sending " + err.Action + " request"
+}
+
+// Unwrap returns the underlying error.
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// IsAuthError reports whether err indicates a bad developer ID or access token.
+func IsAuthError(err error) bool {
+	apiError, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+	if apiError.StatusCode == 401 || apiError.StatusCode == 403 {
+		return true
+	}
+	message := strings.ToLower(apiError.Message)
+	return message == "access denied" || message == "invalid token"
+}
+
+// IsNotFound reports whether err indicates that a developer or offer does not exist.
+func IsNotFound(err error) bool {
+	apiError, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+	if apiError.StatusCode == 404 {
+		return true
+	}
+	return strings.HasPrefix(strings.ToLower(apiError.Message), "no such ")
+}
+
+// IsNetworkError reports whether err occurred before any response was received.
+func IsNetworkError(err error) bool {
+	apiError, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+	return apiError.StatusCode == 0 && apiError.Message == "" && apiError.Err != nil
+}
@@ -0,0 +1,50 @@
+package api_test
+
+import "context"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/api/apitest"
+import "licensezero.com/cli/data"
+import "testing"
+
+func TestAuthError(t *testing.T) {
+	server := apitest.NewServer()
+	defer server.Close()
+	developer := server.AddDeveloper("Jane Dev", "US-CA", "jane@example.com")
+	client := api.NewClient(server.URL, "")
+	err := client.Retract(context.Background(), &data.Developer{DeveloperID: developer.DeveloperID, Token: "wrong"}, "1f838e1d-c98f-44a3-a4e8-15267a0f0777")
+	if !api.IsAuthError(err) {
+		t.Error("not an auth error")
+	}
+	if err.(*api.Error).Action != "retract" {
+		t.Error("wrong action")
+	}
+	if api.IsNotFound(err) {
+		t.Error("reported as not found")
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	server := apitest.NewServer()
+	defer server.Close()
+	client := api.NewClient(server.URL, "")
+	_, err := client.Offering(context.Background(), "1f838e1d-c98f-44a3-a4e8-15267a0f0777")
+	if !api.IsNotFound(err) {
+		t.Error("not a not-found error")
+	}
+	if err.Error() != "no such offer" {
+		t.Error("wrong message")
+	}
+}
+
+func TestNetworkError(t *testing.T) {
+	server := apitest.NewServer()
+	server.Close()
+	client := testClient(server)
+	_, _, err := client.Developer(context.Background(), "1f838e1d-c98f-44a3-a4e8-15267a0f0777")
+	if !api.IsNetworkError(err) {
+		t.Error("not a network error")
+	}
+	if err.(*api.Error).Unwrap() == nil {
+		t.Error("does not wrap cause")
+	}
+}
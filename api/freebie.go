@@ -1,12 +1,9 @@
 package api
 
-import "bytes"
+import "context"
 import "encoding/json"
-import "errors"
 import "licensezero.com/cli/data"
-import "io/ioutil"
 import "net/http"
-import "strconv"
 
 type freebieRequest struct {
 	Action       string      `json:"action"`
@@ -19,12 +16,10 @@ type freebieRequest struct {
 	Term         interface{} `json:"term"`
 }
 
-type freebieResponse struct {
-	Error interface{} `json:"error"`
-}
-
 // Freebie sends freebie API requests.
-func Freebie(developer *data.Developer, offerID, name, jurisdiction, email string, term interface{}) ([]byte, error) {
+// The waiver returned has not been verified.  Freebie also returns
+// the response body, so callers can pass on fields Waiver lacks.
+func (client *Client) Freebie(ctx context.Context, developer *data.Developer, offerID, name, jurisdiction, email string, term interface{}) (*data.Waiver, []byte, error) {
 	bodyData := freebieRequest{
 		Action:       "freebie",
 		DeveloperID:  developer.DeveloperID,
@@ -35,31 +30,15 @@ func Freebie(developer *data.Developer, offerID, name, jurisdiction, email strin
 		EMail:        email,
 		Term:         term,
 	}
-	body, err := json.Marshal(bodyData)
+	var body json.RawMessage
+	err := client.call(ctx, "freebie", bodyData, &body)
 	if err != nil {
-		return nil, errors.New("error serializing request body")
-	}
-	response, err := http.Post("https://licensezero.com/api/v0", "application/json", bytes.NewBuffer(body))
-	if err != nil {
-		return nil, errors.New("error sending request")
-	}
-	defer response.Body.Close()
-	if response.StatusCode != 200 {
-		return nil, errors.New("Server responded " + strconv.Itoa(response.StatusCode))
+		return nil, nil, err
 	}
-	responseBody, err := ioutil.ReadAll(response.Body)
+	var waiver data.Waiver
+	err = json.Unmarshal(body, &waiver)
 	if err != nil {
-		return nil, err
-	}
-	var parsed struct {
-		Error interface{} `json:"error"`
-	}
-	err = json.Unmarshal(responseBody, &parsed)
-	if err != nil {
-		return nil, err
-	}
-	if message, ok := parsed.Error.(string); ok {
-		return nil, errors.New(message)
+		return nil, nil, &Error{Action: "freebie", StatusCode: http.StatusOK, Message: "invalid response", Err: err}
 	}
-	return responseBody, nil
+	return &waiver, body, nil
 }
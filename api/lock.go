@@ -1,14 +1,7 @@
 package api
 
-import "bytes"
-import "encoding/json"
-import "errors"
+import "context"
 import "licensezero.com/cli/data"
-import "io/ioutil"
-import "net/http"
-import "strconv"
-
-import "fmt"
 
 type lockRequest struct {
 	Action      string `json:"action"`
@@ -18,12 +11,8 @@ type lockRequest struct {
 	Unlock      string `json:"unlock"`
 }
 
-type lockResponse struct {
-	Error interface{} `json:"error"`
-}
-
 // Lock sends a lock API request.
-func Lock(developer *data.Developer, offerID string, unlock string) error {
+func (client *Client) Lock(ctx context.Context, developer *data.Developer, offerID string, unlock string) error {
 	bodyData := lockRequest{
 		Action:      "lock",
 		OfferID:     offerID,
@@ -31,30 +20,10 @@ func Lock(developer *data.Developer, offerID string, unlock string) error {
 		DeveloperID: developer.DeveloperID,
 		Token:       developer.Token,
 	}
-	body, err := json.Marshal(bodyData)
-	if err != nil {
-		return err
-	}
-	fmt.Println(string(body))
-	response, err := http.Post("https://licensezero.com/api/v0", "application/json", bytes.NewBuffer(body))
-	if err != nil {
-		return err
-	}
-	if response.StatusCode != 200 {
-		return errors.New("Server responded " + strconv.Itoa(response.StatusCode))
-	}
-	defer response.Body.Close()
-	responseBody, err := ioutil.ReadAll(response.Body)
+	err := client.call(ctx, "lock", bodyData, nil)
 	if err != nil {
 		return err
 	}
-	var parsed lockResponse
-	err = json.Unmarshal(responseBody, &parsed)
-	if err != nil {
-		return err
-	}
-	if message, ok := parsed.Error.(string); ok {
-		return errors.New(message)
-	}
+	client.invalidate("offering", offerID)
 	return nil
 }
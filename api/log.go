@@ -0,0 +1,65 @@
+package api
+
+import "encoding/json"
+import "strconv"
+import "strings"
+
+// redactedKeys lists JSON properties whose values never appear in logs.
+var redactedKeys = map[string]bool{
+	"token":      true,
+	"passphrase": true,
+	"privatekey": true,
+}
+
+const redactedValue = "[REDACTED]"
+
+func (client *Client) logf(format string, arguments ...interface{}) {
+	if client.Logger != nil {
+		client.Logger.Printf(format, arguments...)
+	}
+}
+
+// logBody logs a request or response body, with secrets redacted,
+// if client.LogBodies is set.
+func (client *Client) logBody(label, action string, body []byte) {
+	if client.Logger == nil || !client.LogBodies {
+		return
+	}
+	client.Logger.Printf("%s %s body: %s", action, label, redact(body))
+}
+
+// redact returns a JSON body with secret properties masked.
+// Bodies that are not JSON are summarized, not logged.
+func redact(body []byte) string {
+	var parsed interface{}
+	err := json.Unmarshal(body, &parsed)
+	if err != nil {
+		return "(" + strconv.Itoa(len(body)) + " bytes, not JSON)"
+	}
+	redacted, err := json.Marshal(redactValue(parsed))
+	if err != nil {
+		return "(" + strconv.Itoa(len(body)) + " bytes)"
+	}
+	return string(redacted)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if redactedKeys[strings.ToLower(key)] {
+				typed[key] = redactedValue
+			} else {
+				typed[key] = redactValue(child)
+			}
+		}
+		return typed
+	case []interface{}:
+		for index, child := range typed {
+			typed[index] = redactValue(child)
+		}
+		return typed
+	default:
+		return value
+	}
+}
@@ -0,0 +1,21 @@
+package api
+
+import "strings"
+import "testing"
+
+func TestRedact(t *testing.T) {
+	body := []byte(`{"action":"lock","token":"secret","nested":{"Token":"secret"},"list":[{"token":"secret"}],"offerID":"x"}`)
+	redacted := redact(body)
+	if strings.Contains(redacted, "secret") {
+		t.Error("contains token")
+	}
+	if !strings.Contains(redacted, `"offerID":"x"`) {
+		t.Error("removed other properties")
+	}
+}
+
+func TestRedactNotJSON(t *testing.T) {
+	if strings.Contains(redact([]byte("token=secret")), "secret") {
+		t.Error("contains token")
+	}
+}
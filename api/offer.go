@@ -1,11 +1,7 @@
 package api
 
-import "bytes"
-import "encoding/json"
-import "errors"
+import "context"
 import "licensezero.com/cli/data"
-import "io/ioutil"
-import "net/http"
 import "strings"
 
 // AgencyReference includes the text required in agency terms agreement statements to the API.
@@ -23,15 +19,21 @@ type offerRequest struct {
 }
 
 type offerResponse struct {
-	Error   interface{} `json:"error"`
-	OfferID string      `json:"offerID"`
+	OfferID string `json:"offerID"`
 }
 
-// Offer sends an offer API request.
-func Offer(developer *data.Developer, url, description string, private, relicense uint) (string, error) {
+// NormalizeHomepage adds a scheme to a homepage URL without one,
+// as Offer does before sending it.
+func NormalizeHomepage(url string) string {
 	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
-		url = "http://" + url
+		return "http://" + url
 	}
+	return url
+}
+
+// Offer sends an offer API request.
+func (client *Client) Offer(ctx context.Context, developer *data.Developer, url, description string, private, relicense uint) (string, error) {
+	url = NormalizeHomepage(url)
 	bodyData := offerRequest{
 		Action:      "offer",
 		DeveloperID: developer.DeveloperID,
@@ -44,26 +46,11 @@ func Offer(developer *data.Developer, url, description string, private, relicens
 		},
 		Terms: agencyStatement,
 	}
-	body, err := json.Marshal(bodyData)
-	if err != nil {
-		return "", err
-	}
-	response, err := http.Post("https://licensezero.com/api/v0", "application/json", bytes.NewBuffer(body))
-	if err != nil {
-		return "", errors.New("error sending request")
-	}
-	defer response.Body.Close()
-	responseBody, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return "", err
-	}
 	var parsed offerResponse
-	err = json.Unmarshal(responseBody, &parsed)
+	err := client.call(ctx, "offer", bodyData, &parsed)
 	if err != nil {
 		return "", err
 	}
-	if message, ok := parsed.Error.(string); ok {
-		return "", errors.New(message)
-	}
+	client.invalidate("developer", developer.DeveloperID)
 	return parsed.OfferID, nil
 }
@@ -1,10 +1,6 @@
 package api
 
-import "bytes"
-import "encoding/json"
-import "errors"
-import "io/ioutil"
-import "net/http"
+import "context"
 
 type offeringRequest struct {
 	Action  string `json:"action"`
@@ -20,6 +16,7 @@ type OfferingResponse struct {
 	Description string               `json:"description"`
 	Lock        LockInformation      `json:"lock"`
 	Commission  uint                 `json:"commission"`
+	Retracted   string               `json:"retracted,omitempty"`
 }
 
 // LockInformation represents information about pricing locks on offers.
@@ -30,31 +27,15 @@ type LockInformation struct {
 }
 
 // Offering sends an offering API request.
-func Offering(offerID string) (*OfferingResponse, error) {
+func (client *Client) Offering(ctx context.Context, offerID string) (*OfferingResponse, error) {
 	bodyData := offeringRequest{
 		Action:  "offering",
 		OfferID: offerID,
 	}
-	body, err := json.Marshal(bodyData)
-	if err != nil {
-		return nil, errors.New("error encoding agent key request body")
-	}
-	response, err := http.Post("https://licensezero.com/api/v0", "application/json", bytes.NewBuffer(body))
-	if err != nil {
-		return nil, errors.New("error sending request")
-	}
-	defer response.Body.Close()
-	responseBody, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return nil, errors.New("error reading agent key response body")
-	}
 	var parsed OfferingResponse
-	err = json.Unmarshal(responseBody, &parsed)
+	err := client.cachedCall(ctx, "offering", offerID, bodyData, &parsed)
 	if err != nil {
-		return nil, errors.New("error parsing agent key response body")
-	}
-	if message, ok := parsed.Error.(string); ok {
-		return nil, errors.New(message)
+		return nil, err
 	}
 	return &parsed, nil
 }
@@ -1,13 +1,7 @@
 package api
 
-import "bytes"
-import "encoding/json"
-import "errors"
+import "context"
 import "licensezero.com/cli/data"
-import "io/ioutil"
-import "fmt"
-import "net/http"
-import "strconv"
 
 type raiseRequest struct {
 	Action      string `json:"action"`
@@ -17,12 +11,8 @@ type raiseRequest struct {
 	Commission  uint   `json:"commission"`
 }
 
-type raiseResponse struct {
-	Error interface{} `json:"error"`
-}
-
 // Raise sends raise API requests.
-func Raise(developer *data.Developer, offerID string, commission uint) error {
+func (client *Client) Raise(ctx context.Context, developer *data.Developer, offerID string, commission uint) error {
 	bodyData := raiseRequest{
 		Action:      "raise",
 		DeveloperID: developer.DeveloperID,
@@ -30,30 +20,10 @@ func Raise(developer *data.Developer, offerID string, commission uint) error {
 		Token:       developer.Token,
 		Commission:  commission,
 	}
-	body, err := json.Marshal(bodyData)
-	if err != nil {
-		return err
-	}
-	fmt.Println(bodyData)
-	response, err := http.Post("https://licensezero.com/api/v0", "application/json", bytes.NewBuffer(body))
-	if err != nil {
-		return errors.New("error sending request")
-	}
-	defer response.Body.Close()
-	if response.StatusCode != 200 {
-		return errors.New("Server responded " + strconv.Itoa(response.StatusCode))
-	}
-	responseBody, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return err
-	}
-	var parsed repriceResponse
-	err = json.Unmarshal(responseBody, &parsed)
+	err := client.call(ctx, "raise", bodyData, nil)
 	if err != nil {
 		return err
 	}
-	if message, ok := parsed.Error.(string); ok {
-		return errors.New(message)
-	}
+	client.invalidate("offering", offerID)
 	return nil
 }
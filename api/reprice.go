@@ -1,12 +1,7 @@
 package api
 
-import "bytes"
-import "encoding/json"
-import "errors"
+import "context"
 import "licensezero.com/cli/data"
-import "io/ioutil"
-import "net/http"
-import "strconv"
 
 type repriceRequest struct {
 	Action      string  `json:"action"`
@@ -16,12 +11,8 @@ type repriceRequest struct {
 	Pricing     Pricing `json:"pricing"`
 }
 
-type repriceResponse struct {
-	Error interface{} `json:"error"`
-}
-
 // Reprice sends reprice API requests.
-func Reprice(developer *data.Developer, offerID string, private, relicense uint) error {
+func (client *Client) Reprice(ctx context.Context, developer *data.Developer, offerID string, private, relicense uint) error {
 	bodyData := repriceRequest{
 		Action:      "reprice",
 		DeveloperID: developer.DeveloperID,
@@ -32,29 +23,10 @@ func Reprice(developer *data.Developer, offerID string, private, relicense uint)
 			Relicense: relicense,
 		},
 	}
-	body, err := json.Marshal(bodyData)
-	if err != nil {
-		return err
-	}
-	response, err := http.Post("https://licensezero.com/api/v0", "application/json", bytes.NewBuffer(body))
-	if err != nil {
-		return errors.New("error sending request")
-	}
-	defer response.Body.Close()
-	if response.StatusCode != 200 {
-		return errors.New("Server responded " + strconv.Itoa(response.StatusCode))
-	}
-	responseBody, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return err
-	}
-	var parsed repriceResponse
-	err = json.Unmarshal(responseBody, &parsed)
+	err := client.call(ctx, "reprice", bodyData, nil)
 	if err != nil {
 		return err
 	}
-	if message, ok := parsed.Error.(string); ok {
-		return errors.New(message)
-	}
+	client.invalidate("offering", offerID)
 	return nil
 }
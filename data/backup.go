@@ -0,0 +1,257 @@
+package data
+
+import "archive/tar"
+import "archive/zip"
+import "bytes"
+import "compress/gzip"
+import "crypto/sha256"
+import "encoding/hex"
+import "encoding/json"
+import "errors"
+import "io"
+import "io/ioutil"
+import "path"
+import "sort"
+import "strings"
+import "time"
+
+// BackupManifest describes the files in a backup archive.
+type BackupManifest struct {
+	CLI     string            `json:"cli"`
+	Created string            `json:"created"`
+	Schema  int               `json:"schema"`
+	Files   map[string]string `json:"files"`
+}
+
+// backupManifestName is the name of the manifest in a backup archive.
+const backupManifestName = "manifest.json"
+
+// encryptedBackupForm identifies encrypted backup files.
+const encryptedBackupForm = "licensezero encrypted backup"
+
+type encryptedBackup struct {
+	Form   string  `json:"form"`
+	Sealed *Sealed `json:"sealed"`
+}
+
+// StageBackup copies configuration files, plus a manifest with their
+// SHA-256 checksums, to a new licensezero directory within directory,
+// ready to archive.  cli identifies the program making the backup.
+// It returns the path of the new directory.
+func StageBackup(home, directory, cli string) (string, error) {
+	unlock, err := lockConfig(home)
+	if err != nil {
+		return "", err
+	}
+	files, err := readConfigFiles(home)
+	unlock()
+	if err != nil {
+		return "", err
+	}
+	staged := path.Join(directory, "licensezero")
+	manifest := BackupManifest{
+		CLI:     cli,
+		Created: time.Now().UTC().Format(time.RFC3339),
+		Schema:  SchemaVersion,
+		Files:   make(map[string]string),
+	}
+	for name, content := range files {
+		err = writeFile(path.Join(staged, name), content, 0600)
+		if err != nil {
+			return "", err
+		}
+		manifest.Files[name] = checksum(content)
+	}
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	err = writeFile(path.Join(staged, backupManifestName), append(encoded, '\n'), 0600)
+	if err != nil {
+		return "", err
+	}
+	return staged, nil
+}
+
+func checksum(content []byte) string {
+	digest := sha256.Sum256(content)
+	return hex.EncodeToString(digest[:])
+}
+
+// SealBackup encrypts a backup archive with a passphrase.
+func SealBackup(archive []byte, passphrase string) ([]byte, error) {
+	sealed, err := seal(archive, passphrase, []byte(encryptedBackupForm))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(encryptedBackup{Form: encryptedBackupForm, Sealed: sealed})
+}
+
+// ReadBackup reads and validates a backup made by `licensezero backup`:
+// a tarball, gzipped tarball, or zip file, optionally encrypted.
+// For encrypted backups, it calls passphrase.  Every file must have
+// a known name and valid content, and match the manifest, if any.
+// Cached API responses are skipped.
+func ReadBackup(content []byte, passphrase func() string) (*Backup, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+		var encrypted encryptedBackup
+		err := json.Unmarshal(content, &encrypted)
+		if err != nil || encrypted.Form != encryptedBackupForm || encrypted.Sealed == nil {
+			return nil, errors.New("unknown backup format")
+		}
+		content, err = encrypted.Sealed.open(passphrase(), []byte(encryptedBackupForm))
+		if err != nil {
+			return nil, err
+		}
+	}
+	backup := Backup{Files: make(map[string][]byte)}
+	var err error
+	if bytes.HasPrefix(content, []byte{0x1f, 0x8b}) {
+		var decompressed io.Reader
+		decompressed, err = gzip.NewReader(bytes.NewReader(content))
+		if err == nil {
+			err = backup.readTar(decompressed)
+		}
+	} else if bytes.HasPrefix(content, []byte("PK\x03\x04")) {
+		err = backup.readZip(content)
+	} else {
+		err = backup.readTar(bytes.NewReader(content))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(backup.Files) == 0 {
+		return nil, errors.New("no configuration files")
+	}
+	err = backup.checkManifest()
+	if err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+func (backup *Backup) readTar(reader io.Reader) error {
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		regular := header.Typeflag == tar.TypeReg || header.Typeflag == tar.TypeRegA
+		err = backup.add(header.Name, header.Typeflag == tar.TypeDir, regular, archive)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (backup *Backup) readZip(content []byte) error {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return err
+	}
+	for _, file := range archive.File {
+		mode := file.Mode()
+		if mode.IsDir() {
+			err = backup.add(file.Name, true, false, nil)
+		} else {
+			var reader io.ReadCloser
+			reader, err = file.Open()
+			if err != nil {
+				return err
+			}
+			err = backup.add(file.Name, false, mode.IsRegular(), reader)
+			reader.Close()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add validates and records an archive entry.
+func (backup *Backup) add(entryName string, directory, regular bool, reader io.Reader) error {
+	name, err := backupEntryName(entryName)
+	if err != nil {
+		return err
+	}
+	if directory || name == "" || unversioned(strings.Split(name, "/")[0]) {
+		return nil
+	}
+	if !regular {
+		return errors.New(entryName + " is not a regular file")
+	}
+	if name == backupManifestName {
+		if backup.Manifest != nil {
+			return errors.New("duplicate manifest")
+		}
+		var manifest BackupManifest
+		err = json.NewDecoder(reader).Decode(&manifest)
+		if err != nil {
+			return errors.New("invalid manifest")
+		}
+		backup.Manifest = &manifest
+		return nil
+	}
+	if !knownConfigFile(name) {
+		return errors.New("unexpected file " + entryName)
+	}
+	if _, duplicate := backup.Files[name]; duplicate {
+		return errors.New("duplicate file " + entryName)
+	}
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	err = validateConfigFile(name, content)
+	if err != nil {
+		return errors.New(entryName + ": " + err.Error())
+	}
+	backup.Files[name] = content
+	return nil
+}
+
+// checkManifest verifies that the backup's files match its manifest.
+// Backups made before manifests have none.
+func (backup *Backup) checkManifest() error {
+	manifest := backup.Manifest
+	if manifest == nil {
+		return nil
+	}
+	if manifest.Schema > SchemaVersion {
+		return errors.New("made by a newer version of licensezero")
+	}
+	var names []string
+	for name := range backup.Files {
+		names = append(names, name)
+	}
+	for name := range manifest.Files {
+		if _, ok := backup.Files[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		expected, listed := manifest.Files[name]
+		content, included := backup.Files[name]
+		if !listed {
+			return errors.New(name + " is not in the manifest")
+		}
+		if !included {
+			return errors.New(name + " is missing")
+		}
+		if checksum(content) != expected {
+			return errors.New(name + " does not match its checksum")
+		}
+	}
+	return nil
+}
+
+// WriteBackupFile writes a backup archive readable only by the user.
+func WriteBackupFile(filePath string, content []byte) error {
+	return writeFile(filePath, content, 0600)
+}
@@ -0,0 +1,38 @@
+package data
+
+import "testing"
+
+func TestBackupManifest(t *testing.T) {
+	identity := `{"name":"Test"}`
+	manifest := `{"schema":1,"files":{"identity.json":"` + checksum([]byte(identity)) + `"}}`
+	_, err := ReadBackup(makeTar(t, map[string]string{
+		"licensezero/identity.json": identity,
+		"licensezero/manifest.json": manifest,
+	}).Bytes(), nil)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = ReadBackup(makeTar(t, map[string]string{
+		"licensezero/identity.json": `{"name":"Tampered"}`,
+		"licensezero/manifest.json": manifest,
+	}).Bytes(), nil)
+	if err == nil {
+		t.Error("accepted file that does not match checksum")
+	}
+}
+
+func TestSealBackup(t *testing.T) {
+	archive := makeTar(t, map[string]string{"licensezero/identity.json": `{}`}).Bytes()
+	sealed, err := SealBackup(archive, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup, err := ReadBackup(sealed, func() string { return "passphrase" })
+	if err != nil || len(backup.Files) != 1 {
+		t.Error("could not read sealed backup")
+	}
+	_, err = ReadBackup(sealed, func() string { return "wrong" })
+	if err != ErrWrongPassphrase {
+		t.Error("read sealed backup with wrong passphrase")
+	}
+}
@@ -0,0 +1,72 @@
+package data
+
+import "encoding/json"
+import "io/ioutil"
+import "os"
+import "path"
+import "regexp"
+import "time"
+
+// ResponseCache stores API responses on disk, one file per action and key.
+type ResponseCache struct {
+	Home string
+}
+
+type cachedResponse struct {
+	Stored time.Time       `json:"stored"`
+	Body   json.RawMessage `json:"body"`
+}
+
+var validCacheName = regexp.MustCompile("^[a-zA-Z0-9-]+$")
+
+func (cache *ResponseCache) path(action, key string) (string, bool) {
+	if !validCacheName.MatchString(action) || !validCacheName.MatchString(key) {
+		return "", false
+	}
+	return path.Join(CachePath(cache.Home), action, key+".json"), true
+}
+
+// Get returns a cached response body and when it was stored.
+func (cache *ResponseCache) Get(action, key string) ([]byte, time.Time, bool) {
+	filePath, ok := cache.path(action, key)
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	var cached cachedResponse
+	err = json.Unmarshal(data, &cached)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	return cached.Body, cached.Stored, true
+}
+
+// Put stores a response body.  Failures are ignored.
+func (cache *ResponseCache) Put(action, key string, body []byte) {
+	filePath, ok := cache.path(action, key)
+	if !ok {
+		return
+	}
+	data, err := json.Marshal(cachedResponse{Stored: time.Now(), Body: body})
+	if err != nil {
+		return
+	}
+	writeFile(filePath, data, 0600)
+}
+
+// Delete removes a cached response, if any.
+func (cache *ResponseCache) Delete(action, key string) {
+	filePath, ok := cache.path(action, key)
+	if !ok {
+		return
+	}
+	os.Remove(filePath)
+}
+
+// ClearCache deletes all cached API responses.
+func ClearCache(home string) error {
+	return os.RemoveAll(CachePath(home))
+}
@@ -3,16 +3,103 @@ package data
 import "os"
 import "path"
 
-// ConfigPath computes the path of the CLI's configuration directory.
+// ConfigPath computes the path of the CLI's configuration directory:
+// $LICENSEZERO_CONFIG, else licensezero in $XDG_CONFIG_HOME or ~/.config.
+// If $XDG_CONFIG_HOME has no configuration directory, but ~/.config
+// does, ConfigPath returns the one in ~/.config.
 func ConfigPath(home string) string {
 	fromEnvironment := os.Getenv("LICENSEZERO_CONFIG")
 	if fromEnvironment != "" {
 		return fromEnvironment
 	}
-	return path.Join(home, ".config", "licensezero")
+	if old := OldConfigPath(home); old != "" {
+		return old
+	}
+	return xdgConfigPath(home)
+}
+
+func xdgConfigPath(home string) string {
+	return path.Join(baseDirectory("XDG_CONFIG_HOME", home, ".config"), "licensezero")
+}
+
+// OldConfigPath returns the configuration directory in ~/.config,
+// if ConfigPath falls back to it because $XDG_CONFIG_HOME points
+// elsewhere and has no configuration directory.  Otherwise it
+// returns the empty string.
+func OldConfigPath(home string) string {
+	if os.Getenv("LICENSEZERO_CONFIG") != "" {
+		return ""
+	}
+	current := xdgConfigPath(home)
+	old := path.Join(home, ".config", "licensezero")
+	if current == old {
+		return ""
+	}
+	if _, err := os.Stat(current); !os.IsNotExist(err) {
+		return ""
+	}
+	if info, err := os.Stat(old); err != nil || !info.IsDir() {
+		return ""
+	}
+	return old
+}
+
+// MoveOldConfig moves the configuration directory in ~/.config
+// to $XDG_CONFIG_HOME, if ConfigPath falls back to it.
+// It returns the path it moved the directory to.
+func MoveOldConfig(home string) (string, error) {
+	old := OldConfigPath(home)
+	if old == "" {
+		return "", nil
+	}
+	current := xdgConfigPath(home)
+	err := os.MkdirAll(path.Dir(current), 0700)
+	if err != nil {
+		return "", err
+	}
+	err = os.Rename(old, current)
+	if err != nil {
+		// Rename fails across file systems.
+		err = copyDirectory(old, current)
+		if err != nil {
+			os.RemoveAll(current)
+			return "", err
+		}
+		err = os.RemoveAll(old)
+		if err != nil {
+			return current, err
+		}
+	}
+	return current, nil
+}
+
+// CachePath computes the path of the CLI's API response cache:
+// cache in $LICENSEZERO_CONFIG, else licensezero in $XDG_CACHE_HOME
+// or ~/.cache.
+func CachePath(home string) string {
+	if os.Getenv("LICENSEZERO_CONFIG") != "" {
+		return path.Join(ConfigPath(home), "cache")
+	}
+	return path.Join(baseDirectory("XDG_CACHE_HOME", home, ".cache"), "licensezero")
 }
 
-func makeConfigDirectory(home string) error {
-	path := ConfigPath(home)
-	return os.MkdirAll(path, 0755)
+// StatePath computes the path of the CLI's logs and histories:
+// state in $LICENSEZERO_CONFIG, else licensezero in $XDG_STATE_HOME
+// or ~/.local/state.
+func StatePath(home string) string {
+	if os.Getenv("LICENSEZERO_CONFIG") != "" {
+		return path.Join(ConfigPath(home), "state")
+	}
+	return path.Join(baseDirectory("XDG_STATE_HOME", home, ".local", "state"), "licensezero")
+}
+
+// baseDirectory returns the XDG base directory named by variable,
+// or its default within home.  The XDG Base Directory Specification
+// requires ignoring relative paths.
+func baseDirectory(variable, home string, defaultPath ...string) string {
+	fromEnvironment := os.Getenv(variable)
+	if path.IsAbs(fromEnvironment) {
+		return fromEnvironment
+	}
+	return path.Join(append([]string{home}, defaultPath...)...)
 }
@@ -0,0 +1,73 @@
+package data
+
+import "io/ioutil"
+import "os"
+import "path"
+import "testing"
+
+func TestXDGPaths(t *testing.T) {
+	os.Unsetenv("LICENSEZERO_CONFIG")
+	os.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+	os.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+	defer os.Unsetenv("XDG_CACHE_HOME")
+	os.Setenv("XDG_STATE_HOME", "relative")
+	defer os.Unsetenv("XDG_STATE_HOME")
+	if ConfigPath("/home/user") != "/xdg/config/licensezero" {
+		t.Error("does not honor XDG_CONFIG_HOME")
+	}
+	if CachePath("/home/user") != "/xdg/cache/licensezero" {
+		t.Error("does not honor XDG_CACHE_HOME")
+	}
+	if StatePath("/home/user") != "/home/user/.local/state/licensezero" {
+		t.Error("does not ignore relative XDG_STATE_HOME")
+	}
+	os.Setenv("LICENSEZERO_CONFIG", "/override")
+	defer os.Unsetenv("LICENSEZERO_CONFIG")
+	if ConfigPath("/home/user") != "/override" || CachePath("/home/user") != "/override/cache" {
+		t.Error("does not prefer LICENSEZERO_CONFIG")
+	}
+}
+
+func TestOldConfigPath(t *testing.T) {
+	home, err := ioutil.TempDir("", "licensezero-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	os.Unsetenv("LICENSEZERO_CONFIG")
+	old := path.Join(home, ".config", "licensezero")
+	os.MkdirAll(old, 0700)
+	ioutil.WriteFile(path.Join(old, "identity.json"), []byte(`{"name":"Jane Dev"}`), 0600)
+	xdg := path.Join(home, "xdg")
+	os.Setenv("XDG_CONFIG_HOME", xdg)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+	if ConfigPath(home) != old {
+		t.Fatal("does not fall back to ~/.config")
+	}
+	identity, err := ReadIdentity(home)
+	if err != nil || identity.Name != "Jane Dev" {
+		t.Error("does not read identity from ~/.config")
+	}
+	if _, err := os.Stat(xdg); !os.IsNotExist(err) {
+		t.Error("created XDG directory")
+	}
+	moved, err := MoveOldConfig(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved != path.Join(xdg, "licensezero") || ConfigPath(home) != moved {
+		t.Error("did not move configuration")
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("did not remove old directory")
+	}
+	identity, err = ReadIdentity(home)
+	if err != nil || identity.Name != "Jane Dev" {
+		t.Error("did not move identity")
+	}
+	os.MkdirAll(old, 0700)
+	if ConfigPath(home) != moved {
+		t.Error("prefers ~/.config to existing XDG directory")
+	}
+}
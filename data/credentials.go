@@ -0,0 +1,46 @@
+package data
+
+import "errors"
+import "io/ioutil"
+import "os"
+import "strings"
+
+// ReadCredentials reads the developer ID and access token, from
+// the environment if set, else from the active profile's developer.json.
+//
+// In the environment, $LICENSEZERO_DEVELOPER_ID requires a token
+// from $LICENSEZERO_TOKEN or, if that is not set, the file named by
+// $LICENSEZERO_TOKEN_FILE.  Environment credentials are never
+// combined with credentials from files.
+//
+// ReadCredentials also returns a description of the credentials' source.
+func ReadCredentials(home string) (*Developer, string, error) {
+	developerID := os.Getenv("LICENSEZERO_DEVELOPER_ID")
+	token := os.Getenv("LICENSEZERO_TOKEN")
+	tokenFile := os.Getenv("LICENSEZERO_TOKEN_FILE")
+	if developerID == "" {
+		if token != "" || tokenFile != "" {
+			return nil, "", errors.New("$LICENSEZERO_TOKEN and $LICENSEZERO_TOKEN_FILE require $LICENSEZERO_DEVELOPER_ID")
+		}
+		developer, err := ReadDeveloper(home)
+		if err != nil {
+			return nil, "", err
+		}
+		return developer, developerPath(home), nil
+	}
+	if token != "" {
+		return &Developer{DeveloperID: developerID, Token: token}, "$LICENSEZERO_DEVELOPER_ID and $LICENSEZERO_TOKEN", nil
+	}
+	if tokenFile == "" {
+		return nil, "", errors.New("$LICENSEZERO_DEVELOPER_ID requires $LICENSEZERO_TOKEN or $LICENSEZERO_TOKEN_FILE")
+	}
+	content, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		return nil, "", err
+	}
+	token = strings.TrimSpace(string(content))
+	if token == "" {
+		return nil, "", errors.New(tokenFile + " is empty")
+	}
+	return &Developer{DeveloperID: developerID, Token: token}, "$LICENSEZERO_DEVELOPER_ID and $LICENSEZERO_TOKEN_FILE (" + tokenFile + ")", nil
+}
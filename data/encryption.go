@@ -0,0 +1,128 @@
+package data
+
+import "crypto/rand"
+import "encoding/hex"
+import "errors"
+import "golang.org/x/crypto/chacha20poly1305"
+import "golang.org/x/crypto/scrypt"
+
+// Sealed is data encrypted with XChaCha20-Poly1305,
+// using a key derived from a passphrase with scrypt.
+type Sealed struct {
+	KDF        string `json:"kdf"`
+	N          int    `json:"N"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// ErrWrongPassphrase indicates that sealed data could not be decrypted.
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted data")
+
+// scrypt parameters for newly sealed data.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+func deriveKey(passphrase string, salt []byte, n, r, p int) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, n, r, p, chacha20poly1305.KeySize)
+}
+
+// seal encrypts plaintext with a passphrase.  Opening requires
+// the same additional data, which is authenticated but not encrypted.
+func seal(plaintext []byte, passphrase string, additional []byte) (*Sealed, error) {
+	salt := make([]byte, 16)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+	key, err := deriveKey(passphrase, salt, scryptN, scryptR, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return &Sealed{
+		KDF:        "scrypt",
+		N:          scryptN,
+		R:          scryptR,
+		P:          scryptP,
+		Salt:       hex.EncodeToString(salt),
+		Nonce:      hex.EncodeToString(nonce),
+		Ciphertext: hex.EncodeToString(aead.Seal(nil, nonce, plaintext, additional)),
+	}, nil
+}
+
+// open decrypts data sealed with seal.
+func (sealed *Sealed) open(passphrase string, additional []byte) ([]byte, error) {
+	if sealed.KDF != "scrypt" {
+		return nil, errors.New("unsupported key derivation function")
+	}
+	salt, saltErr := hex.DecodeString(sealed.Salt)
+	nonce, nonceErr := hex.DecodeString(sealed.Nonce)
+	ciphertext, ciphertextErr := hex.DecodeString(sealed.Ciphertext)
+	if saltErr != nil || nonceErr != nil || ciphertextErr != nil {
+		return nil, ErrWrongPassphrase
+	}
+	key, err := deriveKey(passphrase, salt, sealed.N, sealed.R, sealed.P)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additional)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+// Locked reports whether the developer's access token is encrypted
+// and has not been unlocked.
+func (developer *Developer) Locked() bool {
+	return developer.Token == "" && developer.EncryptedToken != nil
+}
+
+// Encrypt seals the developer's access token with a passphrase.
+// The plaintext token remains available until the Developer is written.
+func (developer *Developer) Encrypt(passphrase string) error {
+	if developer.Token == "" {
+		return errors.New("no token to encrypt")
+	}
+	// Bind the token to the developer ID, so it cannot be moved to another.
+	sealed, err := seal([]byte(developer.Token), passphrase, []byte(developer.DeveloperID))
+	if err != nil {
+		return err
+	}
+	developer.EncryptedToken = sealed
+	return nil
+}
+
+// Unlock decrypts the developer's access token with a passphrase.
+func (developer *Developer) Unlock(passphrase string) error {
+	if developer.EncryptedToken == nil {
+		return nil
+	}
+	plaintext, err := developer.EncryptedToken.open(passphrase, []byte(developer.DeveloperID))
+	if err != nil {
+		return err
+	}
+	developer.Token = string(plaintext)
+	return nil
+}
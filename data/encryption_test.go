@@ -0,0 +1,40 @@
+package data
+
+import "testing"
+
+func TestEncryptUnlock(t *testing.T) {
+	developer := Developer{DeveloperID: "developer", Token: "secret"}
+	err := developer.Encrypt("passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	developer.Token = ""
+	if !developer.Locked() {
+		t.Fatal("not locked")
+	}
+	err = developer.Unlock("passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if developer.Token != "secret" {
+		t.Error("wrong token")
+	}
+}
+
+func TestUnlockWrongPassphrase(t *testing.T) {
+	developer := Developer{DeveloperID: "developer", Token: "secret"}
+	developer.Encrypt("passphrase")
+	developer.Token = ""
+	if developer.Unlock("wrong") != ErrWrongPassphrase {
+		t.Error("unlocked with wrong passphrase")
+	}
+}
+
+func TestUnlockOtherDeveloper(t *testing.T) {
+	developer := Developer{DeveloperID: "developer", Token: "secret"}
+	developer.Encrypt("passphrase")
+	other := Developer{DeveloperID: "other", EncryptedToken: developer.EncryptedToken}
+	if other.Unlock("passphrase") != ErrWrongPassphrase {
+		t.Error("unlocked token for another developer")
+	}
+}
@@ -0,0 +1,118 @@
+package data
+
+import "encoding/json"
+import "io/ioutil"
+import "os"
+import "path"
+
+// MalformedFileError indicates a file that could not be parsed as JSON.
+type MalformedFileError struct {
+	Path string
+	Err  error
+}
+
+func (err *MalformedFileError) Error() string {
+	return err.Path + " is not valid JSON: " + err.Err.Error()
+}
+
+// readJSONFile reads and parses a JSON file.
+// Parse errors are returned as *MalformedFileError.
+func readJSONFile(filePath string, value interface{}) error {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(data, value)
+	if err != nil {
+		return &MalformedFileError{Path: filePath, Err: err}
+	}
+	return nil
+}
+
+// writeFile replaces a file atomically, by writing a temporary file
+// in the same directory and renaming it into place.  Readers see
+// either the old file or the new one, never a partial write.
+func writeFile(filePath string, data []byte, mode os.FileMode) error {
+	temporary, err := stageFile(filePath, data, mode)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(temporary, filePath)
+	if err != nil {
+		os.Remove(temporary)
+	}
+	return err
+}
+
+// stageFile writes data to a temporary file in the directory of
+// filePath, ready to rename into place, and returns its path.
+func stageFile(filePath string, data []byte, mode os.FileMode) (string, error) {
+	directory := path.Dir(filePath)
+	err := os.MkdirAll(directory, 0700)
+	if err != nil {
+		return "", err
+	}
+	temporary, err := ioutil.TempFile(directory, "."+path.Base(filePath)+".")
+	if err != nil {
+		return "", err
+	}
+	_, err = temporary.Write(data)
+	if err == nil {
+		err = temporary.Chmod(mode)
+	}
+	if err == nil {
+		err = temporary.Sync()
+	}
+	closeErr := temporary.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(temporary.Name())
+		return "", err
+	}
+	return temporary.Name(), nil
+}
+
+// writeConfigFile marshals value as JSON and writes it to filePath,
+// holding the lock on the configuration directory.
+func writeConfigFile(home, filePath string, value interface{}, mode os.FileMode) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	unlock, err := lockConfig(home)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeFile(filePath, data, mode)
+}
+
+func lockPath(home string) string {
+	return path.Join(ConfigPath(home), ".lock")
+}
+
+// lockConfig takes an advisory lock on the configuration directory,
+// waiting for other licensezero processes to release it.
+// New configuration directories get the current schema version.
+// The caller must call the returned function to release the lock.
+func lockConfig(home string) (func(), error) {
+	err := os.MkdirAll(ConfigPath(home), 0700)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := lockFile(lockPath(home))
+	if err != nil {
+		return nil, err
+	}
+	fresh, err := freshConfig(home)
+	if err == nil && fresh {
+		err = writeVersion(home, SchemaVersion)
+	}
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+	return unlock, nil
+}
@@ -0,0 +1,52 @@
+package data
+
+import "io/ioutil"
+import "os"
+import "path"
+import "testing"
+
+func TestWriteFile(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	filePath := path.Join(directory, "nested", "secret.json")
+	err = writeFile(filePath, []byte("{}"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Error("wrong file mode")
+	}
+	info, err = os.Stat(path.Dir(filePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Error("wrong directory mode")
+	}
+	entries, _ := ioutil.ReadDir(path.Dir(filePath))
+	if len(entries) != 1 {
+		t.Error("left temporary file")
+	}
+}
+
+func TestReadMalformed(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	os.Setenv("LICENSEZERO_CONFIG", directory)
+	defer os.Unsetenv("LICENSEZERO_CONFIG")
+	ioutil.WriteFile(path.Join(directory, "identity.json"), []byte("{"), 0600)
+	_, err = ReadIdentity("")
+	if _, ok := err.(*MalformedFileError); !ok {
+		t.Error("does not report malformed JSON")
+	}
+}
@@ -0,0 +1,89 @@
+package data
+
+import "bufio"
+import "encoding/json"
+import "os"
+import "path"
+import "strings"
+
+// LedgerEntry records an offer made from this machine.
+type LedgerEntry struct {
+	OfferID     string `json:"offerID"`
+	DeveloperID string `json:"developerID"`
+	Homepage    string `json:"homepage"`
+	Description string `json:"description"`
+	Private     uint   `json:"private"`
+	Relicense   uint   `json:"relicense,omitempty"`
+	Created     string `json:"created"`
+	CWD         string `json:"cwd"`
+	Commit      string `json:"commit,omitempty"`
+}
+
+func ledgerPath(home string) string {
+	return path.Join(StatePath(home), "ledger.jsonl")
+}
+
+// AppendLedger records an offer in the ledger,
+// a file of JSON entries, one per line.
+func AppendLedger(home string, entry *LedgerEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(StatePath(home), 0700)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(ledgerPath(home), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	// A single write of a line with O_APPEND does not interleave
+	// with lines written by other processes.
+	_, err = file.Write(append(line, '\n'))
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// ReadLedger reads the ledger, oldest entry first.
+// If there is no ledger, it returns no entries.
+func ReadLedger(home string) ([]LedgerEntry, error) {
+	filePath := ledgerPath(home)
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var entries []LedgerEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry LedgerEntry
+		err = json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			return nil, &MalformedFileError{Path: filePath, Err: err}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Matches reports whether the entry's offer ID, homepage, description,
+// directory, or commit contains search, ignoring case.
+func (entry *LedgerEntry) Matches(search string) bool {
+	search = strings.ToLower(search)
+	for _, field := range []string{entry.OfferID, entry.Homepage, entry.Description, entry.CWD, entry.Commit} {
+		if strings.Contains(strings.ToLower(field), search) {
+			return true
+		}
+	}
+	return false
+}
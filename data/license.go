@@ -0,0 +1,94 @@
+package data
+
+import "encoding/json"
+import "errors"
+import "golang.org/x/crypto/ed25519"
+import "strings"
+
+// License is a signed private license.
+type License struct {
+	Manifest       string `json:"manifest"`
+	Document       string `json:"document"`
+	PublicKey      string `json:"publicKey"`
+	Signature      string `json:"signature"`
+	AgentSignature string `json:"agentSignature"`
+}
+
+// LicenseManifest describes the parties and terms of a License.
+type LicenseManifest struct {
+	Form     string         `json:"FORM"`
+	Version  string         `json:"VERSION"`
+	Date     string         `json:"date"`
+	Licensee Party          `json:"licensee"`
+	Licensor Party          `json:"licensor"`
+	Offer    OfferReference `json:"offer"`
+	Price    uint           `json:"price"`
+}
+
+// LicenseForm is the FORM of private license manifests.
+const LicenseForm = "private license"
+
+// WaiverForm is the FORM of waiver manifests.
+const WaiverForm = "waiver"
+
+// ParseLicense parses a JSON private license.
+func ParseLicense(data []byte) (*License, error) {
+	var license License
+	err := json.Unmarshal(data, &license)
+	if err != nil {
+		return nil, err
+	}
+	if license.Manifest == "" || license.Document == "" {
+		return nil, errors.New("missing manifest or document")
+	}
+	return &license, nil
+}
+
+// ParseManifest parses the license's manifest.
+func (license *License) ParseManifest() (*LicenseManifest, error) {
+	var manifest LicenseManifest
+	err := json.Unmarshal([]byte(license.Manifest), &manifest)
+	if err != nil {
+		return nil, errors.New("invalid manifest")
+	}
+	if manifest.Form != LicenseForm {
+		return nil, errors.New("not a private license")
+	}
+	return &manifest, nil
+}
+
+// Verify checks the license's signatures by the licensor and the agent,
+// and that its manifest matches its document and names the licensor's key.
+func (license *License) Verify(licensorKey, agentKey ed25519.PublicKey) error {
+	manifest, err := license.ParseManifest()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(license.Document, license.Manifest+"\n\n") {
+		return errors.New("document does not match manifest")
+	}
+	if !sameKey(manifest.Licensor.PublicKey, licensorKey) {
+		return errors.New("manifest does not name the licensor's public key")
+	}
+	return verifySignatures(license.Document, license.PublicKey, license.Signature, license.AgentSignature, licensorKey, agentKey)
+}
+
+// DocumentForm returns the FORM of a JSON license or waiver,
+// like LicenseForm or WaiverForm.
+func DocumentForm(data []byte) (string, error) {
+	var document struct {
+		Manifest string `json:"manifest"`
+	}
+	err := json.Unmarshal(data, &document)
+	if err != nil {
+		return "", errors.New("not JSON")
+	}
+	var manifest struct {
+		Form string `json:"FORM"`
+	}
+	err = json.Unmarshal([]byte(document.Manifest), &manifest)
+	if err != nil || manifest.Form == "" {
+		return "", errors.New("missing or invalid manifest")
+	}
+	return manifest.Form, nil
+}
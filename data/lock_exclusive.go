@@ -0,0 +1,64 @@
+package data
+
+import "errors"
+import "io/ioutil"
+import "os"
+import "strconv"
+import "strings"
+import "time"
+
+// lockTimeout limits how long lockFile waits for another process.
+const lockTimeout = 10 * time.Second
+
+// lockRetry is how often lockFile tries again.
+const lockRetry = 100 * time.Millisecond
+
+// lockTimeoutError reports a lock another process held too long.
+func lockTimeoutError(filePath string) error {
+	return errors.New("timed out waiting for lock " + filePath + lockHolder(filePath) +
+		". If licensezero is not running, delete " + filePath)
+}
+
+// exclusiveLock takes a lock by creating filePath exclusively,
+// retrying until another process removes it or until timeout.
+// The file records the process ID of its holder.  A lock file
+// older than stale is left from a crash, and is replaced.
+func exclusiveLock(filePath string, timeout, stale time.Duration) (func(), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		file, err := os.OpenFile(filePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, err = file.WriteString(strconv.Itoa(os.Getpid()) + "\n")
+			file.Close()
+			if err != nil {
+				os.Remove(filePath)
+				return nil, err
+			}
+			return func() { os.Remove(filePath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		info, err := os.Stat(filePath)
+		if err == nil && time.Since(info.ModTime()) > stale {
+			os.Remove(filePath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, lockTimeoutError(filePath)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+func lockHolder(filePath string) string {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return ""
+	}
+	pid := strings.TrimSpace(string(content))
+	if _, err := strconv.Atoi(pid); err != nil {
+		return ""
+	}
+	return " held by process " + pid
+}
@@ -0,0 +1,15 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package data
+
+import "time"
+
+// staleLockAge is how old a lock file must be to treat it as
+// left behind by a crash.  Configuration writes take far less.
+const staleLockAge = time.Minute
+
+// lockFile takes a lock by creating filePath exclusively.
+func lockFile(filePath string) (func(), error) {
+	return exclusiveLock(filePath, lockTimeout, staleLockAge)
+}
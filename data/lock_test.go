@@ -0,0 +1,49 @@
+package data
+
+import "io/ioutil"
+import "os"
+import "path"
+import "strings"
+import "testing"
+import "time"
+
+func TestExclusiveLock(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	filePath := path.Join(directory, ".lock")
+	unlock, err := exclusiveLock(filePath, time.Second, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = exclusiveLock(filePath, 200*time.Millisecond, time.Minute)
+	if err == nil {
+		t.Fatal("took held lock")
+	}
+	if !strings.Contains(err.Error(), "delete "+filePath) {
+		t.Error("does not name lock file: " + err.Error())
+	}
+	unlock()
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		t.Error("did not remove lock file")
+	}
+}
+
+func TestExclusiveLockStale(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	filePath := path.Join(directory, ".lock")
+	ioutil.WriteFile(filePath, []byte("12345\n"), 0600)
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(filePath, old, old)
+	unlock, err := exclusiveLock(filePath, 200*time.Millisecond, time.Minute)
+	if err != nil {
+		t.Fatal("did not break stale lock: " + err.Error())
+	}
+	unlock()
+}
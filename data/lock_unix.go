@@ -0,0 +1,46 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package data
+
+import "os"
+import "strconv"
+import "syscall"
+import "time"
+
+// lockFile takes an exclusive flock on filePath, creating it if needed,
+// and records the process ID of the holder in it.  Like the lock on
+// other platforms, it gives up after lockTimeout.
+func lockFile(filePath string) (func(), error) {
+	return flockFile(filePath, lockTimeout)
+}
+
+func flockFile(filePath string, timeout time.Duration) (func(), error) {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			break
+		}
+		if err != syscall.EWOULDBLOCK && err != syscall.EINTR {
+			file.Close()
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, lockTimeoutError(filePath)
+		}
+		time.Sleep(lockRetry)
+	}
+	if file.Truncate(0) == nil {
+		file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return func() {
+		syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
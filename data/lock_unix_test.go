@@ -0,0 +1,47 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package data
+
+import "io/ioutil"
+import "os"
+import "path"
+import "strconv"
+import "strings"
+import "testing"
+import "time"
+
+func TestFlockTimeout(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	filePath := path.Join(directory, ".lock")
+	unlock, err := flockFile(filePath, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each open file description takes its own flock,
+	// so a second attempt conflicts even in this process.
+	_, err = flockFile(filePath, 200*time.Millisecond)
+	if err == nil {
+		t.Fatal("took held lock")
+	}
+	message := err.Error()
+	if !strings.Contains(message, "timed out waiting for lock") {
+		t.Error("unexpected error: " + message)
+	}
+	if !strings.Contains(message, "process "+strconv.Itoa(os.Getpid())) {
+		t.Error("does not name holder: " + message)
+	}
+	if !strings.Contains(message, "delete "+filePath) {
+		t.Error("does not name lock file: " + message)
+	}
+	unlock()
+	unlock, err = flockFile(filePath, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal("did not take released lock: " + err.Error())
+	}
+	unlock()
+}
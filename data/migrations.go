@@ -0,0 +1,285 @@
+package data
+
+import "encoding/json"
+import "errors"
+import "io"
+import "io/ioutil"
+import "os"
+import "path"
+import "path/filepath"
+import "strconv"
+import "strings"
+import "time"
+
+// Migration upgrades the configuration directory
+// from the previous schema version to Version.
+type Migration struct {
+	Version     int
+	Description string
+	migrate     func(home string) error
+}
+
+// migrations lists every schema change, in order.
+// Append new migrations.  Never edit or reorder old ones.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "Replace legacy licensor.json with developer.json.",
+		migrate:     migrateLicensor,
+	},
+	{
+		Version:     2,
+		Description: "Remove cached API responses, now stored in the cache directory.",
+		migrate:     migrateCache,
+	},
+	{
+		Version:     3,
+		Description: "Make the configuration directory and files readable only by you.",
+		migrate:     migratePermissions,
+	},
+}
+
+// SchemaVersion is the configuration schema version this build writes.
+var SchemaVersion = migrations[len(migrations)-1].Version
+
+func versionPath(home string) string {
+	return path.Join(ConfigPath(home), "version")
+}
+
+// ConfigVersion returns the schema version of the configuration directory.
+// A missing or empty directory has the current version.
+// A directory without a version file predates versioning, version 0.
+func ConfigVersion(home string) (int, error) {
+	fresh, err := freshConfig(home)
+	if err != nil {
+		return 0, err
+	}
+	if fresh {
+		return SchemaVersion, nil
+	}
+	content, err := ioutil.ReadFile(versionPath(home))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(content)))
+}
+
+// freshConfig reports whether the configuration directory
+// holds no configuration files yet.
+func freshConfig(home string) (bool, error) {
+	entries, err := ioutil.ReadDir(ConfigPath(home))
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if !unversioned(entry.Name()) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// unversioned reports whether a file in the configuration directory
+// is outside the schema: cached API responses, state, and the lock file.
+func unversioned(name string) bool {
+	return name == "cache" || name == "state" || name == ".lock"
+}
+
+func writeVersion(home string, version int) error {
+	return writeFile(versionPath(home), []byte(strconv.Itoa(version)+"\n"), 0600)
+}
+
+// ErrNewerConfig indicates a configuration directory with a schema
+// version newer than SchemaVersion.
+var ErrNewerConfig = errors.New("made by a newer version of licensezero")
+
+// PendingMigrations returns the migrations not yet applied, in order.
+// It returns ErrNewerConfig if the configuration directory is newer
+// than this version of the CLI understands.
+func PendingMigrations(home string) ([]Migration, error) {
+	version, err := ConfigVersion(home)
+	if err != nil {
+		return nil, err
+	}
+	if version > SchemaVersion {
+		return nil, ErrNewerConfig
+	}
+	var pending []Migration
+	for _, migration := range migrations {
+		if migration.Version > version {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// BackupPath computes where Migrate will back up the configuration directory.
+func BackupPath(home string, now time.Time) string {
+	return ConfigPath(home) + "-backup-" + now.UTC().Format("20060102T150405Z")
+}
+
+// Migrate applies pending migrations, after copying the configuration
+// directory to BackupPath.  It returns the migrations applied and
+// the path of the backup, if any.
+func Migrate(home string) ([]Migration, string, error) {
+	// Avoid locking, and creating the directory, when up to date.
+	pending, err := PendingMigrations(home)
+	if err != nil || len(pending) == 0 {
+		return nil, "", err
+	}
+	unlock, err := lockConfig(home)
+	if err != nil {
+		return nil, "", err
+	}
+	defer unlock()
+	// Another process may have migrated while we waited for the lock.
+	pending, err = PendingMigrations(home)
+	if err != nil || len(pending) == 0 {
+		return nil, "", err
+	}
+	backup := BackupPath(home, time.Now())
+	err = copyDirectory(ConfigPath(home), backup)
+	if err != nil {
+		return nil, "", err
+	}
+	var applied []Migration
+	for _, migration := range pending {
+		err = migration.migrate(home)
+		if err != nil {
+			return applied, backup, err
+		}
+		err = writeVersion(home, migration.Version)
+		if err != nil {
+			return applied, backup, err
+		}
+		applied = append(applied, migration)
+	}
+	return applied, backup, nil
+}
+
+// copyDirectory copies configuration files, skipping unversioned files.
+func copyDirectory(source, destination string) error {
+	err := os.MkdirAll(destination, 0700)
+	if err != nil {
+		return err
+	}
+	entries, err := ioutil.ReadDir(source)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if unversioned(name) {
+			continue
+		}
+		from := path.Join(source, name)
+		to := path.Join(destination, name)
+		if entry.IsDir() {
+			err = copyDirectory(from, to)
+		} else if entry.Mode().IsRegular() {
+			err = copyFile(from, to)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(source, destination string) error {
+	input, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+	output, err := os.OpenFile(destination, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(output, input)
+	closeErr := output.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+type legacyLicensor struct {
+	Token      string `json:"token"`
+	LicensorID string `json:"licensorID"`
+}
+
+// migrateLicensor replaces licensor.json, from before developers
+// were called developers, with developer.json.  Only the default
+// profile predates developer.json.
+func migrateLicensor(home string) error {
+	licensorPath := path.Join(ConfigPath(home), "licensor.json")
+	var licensor legacyLicensor
+	err := readJSONFile(licensorPath, &licensor)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	developerPath := path.Join(ConfigPath(home), "developer.json")
+	_, err = os.Stat(developerPath)
+	if os.IsNotExist(err) {
+		data, err := json.Marshal(Developer{
+			Token:       licensor.Token,
+			DeveloperID: licensor.LicensorID,
+		})
+		if err != nil {
+			return err
+		}
+		err = writeFile(developerPath, data, 0600)
+		if err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	return os.Remove(licensorPath)
+}
+
+// migrateCache removes the API response cache from the configuration
+// directory, unless it is still the cache directory.
+func migrateCache(home string) error {
+	legacy := path.Join(ConfigPath(home), "cache")
+	if CachePath(home) == legacy {
+		return nil
+	}
+	return os.RemoveAll(legacy)
+}
+
+// migratePermissions restricts directories and files in the
+// configuration directory, which older versions created
+// readable by everyone.  Creating a directory or writing
+// a file does not change the mode of one that exists.
+func migratePermissions(home string) error {
+	root := ConfigPath(home)
+	return filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if filepath.Dir(filePath) == filepath.Clean(root) && unversioned(info.Name()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return os.Chmod(filePath, 0700)
+		}
+		if info.Mode().IsRegular() {
+			return os.Chmod(filePath, 0600)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,125 @@
+package data
+
+import "io/ioutil"
+import "os"
+import "path"
+import "testing"
+
+func TestMigrateLicensor(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	os.Setenv("LICENSEZERO_CONFIG", directory)
+	defer os.Unsetenv("LICENSEZERO_CONFIG")
+	legacy := []byte(`{"licensorID":"developer","token":"secret"}`)
+	ioutil.WriteFile(path.Join(directory, "licensor.json"), legacy, 0600)
+	pending, err := PendingMigrations("")
+	if err != nil || len(pending) != len(migrations) {
+		t.Fatal("wrong pending migrations")
+	}
+	applied, backup, err := Migrate("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(backup)
+	if len(applied) != len(migrations) {
+		t.Error("did not apply migrations")
+	}
+	developer, err := ReadDeveloper("")
+	if err != nil || developer.DeveloperID != "developer" || developer.Token != "secret" {
+		t.Error("did not migrate licensor.json")
+	}
+	if _, err := os.Stat(path.Join(directory, "licensor.json")); !os.IsNotExist(err) {
+		t.Error("did not remove licensor.json")
+	}
+	saved, err := ioutil.ReadFile(path.Join(backup, "licensor.json"))
+	if err != nil || string(saved) != string(legacy) {
+		t.Error("did not back up licensor.json")
+	}
+	version, err := ConfigVersion("")
+	if err != nil || version != SchemaVersion {
+		t.Error("did not record version")
+	}
+}
+
+func TestMigrateFresh(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	os.Setenv("LICENSEZERO_CONFIG", path.Join(directory, "config"))
+	defer os.Unsetenv("LICENSEZERO_CONFIG")
+	applied, backup, err := Migrate("")
+	if err != nil || len(applied) != 0 || backup != "" {
+		t.Error("migrated fresh configuration")
+	}
+	err = WriteIdentity("", &Identity{Name: "Test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	version, err := ConfigVersion("")
+	if err != nil || version != SchemaVersion {
+		t.Error("did not record version for new configuration")
+	}
+}
+
+func TestMigrateNewer(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	os.Setenv("LICENSEZERO_CONFIG", directory)
+	defer os.Unsetenv("LICENSEZERO_CONFIG")
+	ioutil.WriteFile(path.Join(directory, "version"), []byte("99\n"), 0600)
+	_, backup, err := Migrate("")
+	if err != ErrNewerConfig {
+		t.Errorf("wrong error: %v", err)
+	}
+	if backup != "" {
+		t.Error("backed up newer configuration")
+	}
+}
+
+func TestMigratePermissions(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	os.Setenv("LICENSEZERO_CONFIG", directory)
+	defer os.Unsetenv("LICENSEZERO_CONFIG")
+	profile := path.Join(directory, "profiles", "work")
+	os.MkdirAll(profile, 0755)
+	os.Chmod(directory, 0755)
+	os.Chmod(path.Join(directory, "profiles"), 0755)
+	files := []string{
+		path.Join(directory, "developer.json"),
+		path.Join(directory, "identity.json"),
+		path.Join(profile, "developer.json"),
+	}
+	for _, file := range files {
+		ioutil.WriteFile(file, []byte(`{"developerID":"developer","token":"secret"}`), 0644)
+	}
+	ioutil.WriteFile(path.Join(directory, "version"), []byte("2\n"), 0644)
+	_, backup, err := Migrate("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(backup)
+	for _, dir := range []string{directory, path.Join(directory, "profiles"), profile} {
+		info, err := os.Stat(dir)
+		if err != nil || info.Mode().Perm() != 0700 {
+			t.Errorf("%s is not 0700", dir)
+		}
+	}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil || info.Mode().Perm() != 0600 {
+			t.Errorf("%s is not 0600", file)
+		}
+	}
+}
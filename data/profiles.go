@@ -0,0 +1,124 @@
+package data
+
+import "io/ioutil"
+import "os"
+import "path"
+import "regexp"
+import "sort"
+import "strings"
+
+// DefaultProfile names the profile stored directly in the configuration directory.
+const DefaultProfile = "default"
+
+var validProfileName = regexp.MustCompile("^[a-zA-Z0-9][a-zA-Z0-9_-]*$")
+
+// ValidProfileName reports whether a profile name is acceptable.
+func ValidProfileName(name string) bool {
+	return validProfileName.MatchString(name)
+}
+
+func profilesPath(home string) string {
+	return path.Join(ConfigPath(home), "profiles")
+}
+
+func currentProfilePath(home string) string {
+	return path.Join(ConfigPath(home), "profile")
+}
+
+// ProfilePath computes the path of the directory holding a profile's files.
+func ProfilePath(home, profile string) string {
+	if profile == DefaultProfile {
+		return ConfigPath(home)
+	}
+	return path.Join(profilesPath(home), profile)
+}
+
+// ActiveProfile returns the name of the profile in use:
+// $LICENSEZERO_PROFILE if set, else the profile chosen with UseProfile,
+// else DefaultProfile.
+func ActiveProfile(home string) string {
+	fromEnvironment := os.Getenv("LICENSEZERO_PROFILE")
+	if fromEnvironment != "" {
+		return fromEnvironment
+	}
+	data, err := ioutil.ReadFile(currentProfilePath(home))
+	if err == nil {
+		chosen := strings.TrimSpace(string(data))
+		if ValidProfileName(chosen) {
+			return chosen
+		}
+	}
+	return DefaultProfile
+}
+
+func activeProfilePath(home string) string {
+	return ProfilePath(home, ActiveProfile(home))
+}
+
+// ProfileExists reports whether a profile has been created.
+func ProfileExists(home, profile string) bool {
+	if profile == DefaultProfile {
+		return true
+	}
+	info, err := os.Stat(ProfilePath(home, profile))
+	return err == nil && info.IsDir()
+}
+
+// ListProfiles returns the names of all profiles, sorted.
+func ListProfiles(home string) ([]string, error) {
+	names := []string{DefaultProfile}
+	entries, err := ioutil.ReadDir(profilesPath(home))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return names, nil
+		}
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && ValidProfileName(entry.Name()) && entry.Name() != DefaultProfile {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// UseProfile makes a profile active for future runs, creating it if needed.
+func UseProfile(home, profile string) error {
+	unlock, err := lockConfig(home)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	err = os.MkdirAll(ProfilePath(home, profile), 0700)
+	if err != nil {
+		return err
+	}
+	if profile == DefaultProfile {
+		err = os.Remove(currentProfilePath(home))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return writeFile(currentProfilePath(home), []byte(profile+"\n"), 0600)
+}
+
+// RemoveProfile deletes a named profile and its files.
+// If the profile was in use, the default profile becomes active.
+func RemoveProfile(home, profile string) error {
+	unlock, err := lockConfig(home)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	err = os.RemoveAll(ProfilePath(home, profile))
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(currentProfilePath(home))
+	if err == nil && strings.TrimSpace(string(data)) == profile {
+		return os.Remove(currentProfilePath(home))
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package data
+
+import "bytes"
+import "encoding/json"
+import "errors"
+import "io"
+import "io/ioutil"
+import "os"
+import "path"
+import "strings"
+
+// ProjectOffer is License Zero metadata recorded in a project,
+// so users can find the offer and verify licenses for it.
+type ProjectOffer struct {
+	OfferID     string         `json:"offerID"`
+	Developer   Party          `json:"developer"`
+	Homepage    string         `json:"homepage"`
+	Description string         `json:"description"`
+	Pricing     ProjectPricing `json:"pricing"`
+}
+
+// ProjectPricing records an offer's pricing, in US cents.
+type ProjectPricing struct {
+	Private   uint `json:"private"`
+	Relicense uint `json:"relicense,omitempty"`
+}
+
+// projectMetadataKey is the key for License Zero metadata,
+// both in package.json and in .licensezero.json.
+const projectMetadataKey = "licensezero"
+
+// ProjectMetadataFile is the metadata file for projects without package.json.
+const ProjectMetadataFile = ".licensezero.json"
+
+// ErrInvalidProjectMetadata indicates a licensezero property
+// that is not an array of offers.
+var ErrInvalidProjectMetadata = errors.New("invalid " + projectMetadataKey + " metadata")
+
+// InvalidMetadataError indicates a valid JSON file
+// with invalid License Zero metadata.
+type InvalidMetadataError struct {
+	Path string
+}
+
+func (err *InvalidMetadataError) Error() string {
+	return "invalid " + projectMetadataKey + " metadata in " + err.Path
+}
+
+// StampProject records an offer in package.json in directory, if there
+// is one, else in .licensezero.json, replacing any record of the same
+// offer.  It returns the name of the file written.
+func StampProject(directory string, offer *ProjectOffer) (string, error) {
+	name := "package.json"
+	content, err := ioutil.ReadFile(path.Join(directory, name))
+	if os.IsNotExist(err) {
+		name = ProjectMetadataFile
+		content, err = ioutil.ReadFile(path.Join(directory, name))
+		if os.IsNotExist(err) {
+			content, err = []byte("{}\n"), nil
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	filePath := path.Join(directory, name)
+	object, err := readOrderedObject(content)
+	if err != nil {
+		return "", &MalformedFileError{Path: filePath, Err: err}
+	}
+	entries, err := projectEntries(content)
+	if err == ErrInvalidProjectMetadata {
+		return "", &InvalidMetadataError{Path: filePath}
+	}
+	if err != nil {
+		return "", &MalformedFileError{Path: filePath, Err: err}
+	}
+	stamped, err := json.Marshal(offer)
+	if err != nil {
+		return "", err
+	}
+	// Keep other entries as they are, including signed legacy entries.
+	replaced := false
+	for i, entry := range entries {
+		existing, err := parseProjectOffer(entry)
+		if err != nil {
+			return "", &InvalidMetadataError{Path: filePath}
+		}
+		if existing.OfferID == offer.OfferID {
+			entries[i] = stamped
+			replaced = true
+		}
+	}
+	if !replaced {
+		entries = append(entries, stamped)
+	}
+	encoded, err := json.Marshal(entries)
+	if err != nil {
+		return "", err
+	}
+	object.set(projectMetadataKey, encoded)
+	output, err := object.marshal(detectIndent(content))
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(filePath)
+	mode := os.FileMode(0644)
+	if err == nil {
+		mode = info.Mode().Perm()
+	}
+	return name, writeFile(filePath, output, mode)
+}
+
+// SetPackageLicense sets the license property of package.json in
+// directory to an SPDX expression, keeping the order of properties.
+// It reports whether there was a package.json to change.
+func SetPackageLicense(directory, expression string) (bool, error) {
+	filePath := path.Join(directory, "package.json")
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	object, err := readOrderedObject(content)
+	if err != nil {
+		return false, &MalformedFileError{Path: filePath, Err: err}
+	}
+	encoded, err := json.Marshal(expression)
+	if err != nil {
+		return false, err
+	}
+	object.set("license", encoded)
+	output, err := object.marshal(detectIndent(content))
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return false, err
+	}
+	return true, writeFile(filePath, output, info.Mode().Perm())
+}
+
+// ParseProjectOffers reads License Zero metadata from the content
+// of package.json or .licensezero.json.  It reads both offers as
+// StampProject writes them and legacy entries, which nest the offer
+// or project ID under license.  It returns ErrInvalidProjectMetadata
+// if the JSON is valid but the metadata is not.
+func ParseProjectOffers(content []byte) ([]ProjectOffer, error) {
+	entries, err := projectEntries(content)
+	if err != nil {
+		return nil, err
+	}
+	var offers []ProjectOffer
+	for _, entry := range entries {
+		offer, err := parseProjectOffer(entry)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, *offer)
+	}
+	return offers, nil
+}
+
+// projectEntries returns the entries of the licensezero array.
+func projectEntries(content []byte) ([]json.RawMessage, error) {
+	var parsed map[string]json.RawMessage
+	err := json.Unmarshal(content, &parsed)
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := parsed[projectMetadataKey]
+	if !ok {
+		return nil, nil
+	}
+	var entries []json.RawMessage
+	err = json.Unmarshal(raw, &entries)
+	if err != nil {
+		return nil, ErrInvalidProjectMetadata
+	}
+	return entries, nil
+}
+
+// legacyProjectOffer is metadata written by earlier versions of
+// licensezero, with signatures over the license object.
+type legacyProjectOffer struct {
+	License struct {
+		OfferID      string `json:"offerID"`
+		ProjectID    string `json:"projectID"`
+		Name         string `json:"name"`
+		Jurisdiction string `json:"jurisdiction"`
+		PublicKey    string `json:"publicKey"`
+		Homepage     string `json:"homepage"`
+		Description  string `json:"description"`
+	} `json:"license"`
+}
+
+func parseProjectOffer(entry json.RawMessage) (*ProjectOffer, error) {
+	var offer ProjectOffer
+	err := json.Unmarshal(entry, &offer)
+	if err != nil {
+		return nil, ErrInvalidProjectMetadata
+	}
+	if offer.OfferID != "" {
+		return &offer, nil
+	}
+	var legacy legacyProjectOffer
+	err = json.Unmarshal(entry, &legacy)
+	if err != nil {
+		return nil, ErrInvalidProjectMetadata
+	}
+	license := legacy.License
+	offer.OfferID = license.OfferID
+	if offer.OfferID == "" {
+		offer.OfferID = license.ProjectID
+	}
+	offer.Developer = Party{
+		Name:         license.Name,
+		Jurisdiction: license.Jurisdiction,
+		PublicKey:    license.PublicKey,
+	}
+	offer.Homepage = license.Homepage
+	offer.Description = license.Description
+	return &offer, nil
+}
+
+// orderedObject is a JSON object that keeps the order of its keys,
+// so rewriting package.json does not reorder it.
+type orderedObject struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+func readOrderedObject(content []byte) (*orderedObject, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token != json.Delim('{') {
+		return nil, errors.New("not a JSON object")
+	}
+	object := orderedObject{values: make(map[string]json.RawMessage)}
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+		var value json.RawMessage
+		err = decoder.Decode(&value)
+		if err != nil {
+			return nil, err
+		}
+		if _, duplicate := object.values[key]; !duplicate {
+			object.keys = append(object.keys, key)
+		}
+		object.values[key] = value
+	}
+	_, err = decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	_, err = decoder.Token()
+	if err != io.EOF {
+		return nil, errors.New("trailing data after JSON object")
+	}
+	return &object, nil
+}
+
+func (object *orderedObject) set(key string, value json.RawMessage) {
+	if _, exists := object.values[key]; !exists {
+		object.keys = append(object.keys, key)
+	}
+	object.values[key] = value
+}
+
+func (object *orderedObject) marshal(indent string) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString("{")
+	for i, key := range object.keys {
+		if i != 0 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString("\n" + indent)
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(encodedKey)
+		buffer.WriteString(": ")
+		var compacted bytes.Buffer
+		err = json.Compact(&compacted, object.values[key])
+		if err != nil {
+			return nil, err
+		}
+		err = json.Indent(&buffer, compacted.Bytes(), indent, indent)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(object.keys) != 0 {
+		buffer.WriteString("\n")
+	}
+	buffer.WriteString("}\n")
+	return buffer.Bytes(), nil
+}
+
+// detectIndent returns the indentation of the first indented line,
+// or two spaces.
+func detectIndent(content []byte) string {
+	for _, line := range strings.Split(string(content), "\n")[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "  "
+}
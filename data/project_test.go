@@ -0,0 +1,109 @@
+package data
+
+import "io/ioutil"
+import "os"
+import "path"
+import "strings"
+import "testing"
+
+func TestStampProjectMetadataFile(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	first := ProjectOffer{OfferID: "first", Pricing: ProjectPricing{Private: 100}}
+	second := ProjectOffer{OfferID: "second", Pricing: ProjectPricing{Private: 200}}
+	for _, offer := range []ProjectOffer{first, second} {
+		written, err := StampProject(directory, &offer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written != ProjectMetadataFile {
+			t.Fatal("wrote " + written)
+		}
+	}
+	first.Pricing.Private = 300
+	_, err = StampProject(directory, &first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(path.Join(directory, ProjectMetadataFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	offers, err := ParseProjectOffers(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(offers) != 2 || offers[0].OfferID != "first" || offers[1].OfferID != "second" {
+		t.Fatal("wrong offers")
+	}
+	if offers[0].Pricing.Private != 300 {
+		t.Error("did not replace offer")
+	}
+}
+
+func TestStampProjectInvalid(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	err = ioutil.WriteFile(path.Join(directory, "package.json"), []byte("[]"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = StampProject(directory, &ProjectOffer{OfferID: "offer"})
+	if _, ok := err.(*MalformedFileError); !ok {
+		t.Error("accepted non-object package.json")
+	}
+}
+
+func TestStampProjectInvalidMetadata(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	err = ioutil.WriteFile(path.Join(directory, "package.json"), []byte(`{"licensezero":{"note":"x"}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = StampProject(directory, &ProjectOffer{OfferID: "offer"})
+	if _, ok := err.(*InvalidMetadataError); !ok {
+		t.Errorf("wrong error: %v", err)
+	}
+}
+
+func TestLegacyProjectOffers(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	legacy := `{"license":{"projectID":"legacy","homepage":"https://example.com"},"licensorSignature":"aa","agentSignature":"bb"}`
+	packageJSON := path.Join(directory, "package.json")
+	err = ioutil.WriteFile(packageJSON, []byte(`{"licensezero":[`+legacy+`]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = StampProject(directory, &ProjectOffer{OfferID: "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(packageJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offers, err := ParseProjectOffers(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(offers) != 2 || offers[0].OfferID != "legacy" || offers[0].Homepage != "https://example.com" || offers[1].OfferID != "new" {
+		t.Errorf("wrong offers: %v", offers)
+	}
+	if !strings.Contains(string(content), `"licensorSignature": "aa"`) {
+		t.Error("did not keep legacy signatures")
+	}
+}
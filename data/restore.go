@@ -0,0 +1,279 @@
+package data
+
+import "bytes"
+import "encoding/json"
+import "errors"
+import "io/ioutil"
+import "os"
+import "path"
+import "path/filepath"
+import "regexp"
+import "sort"
+import "strconv"
+import "strings"
+import "time"
+
+// Backup holds configuration files read from a backup archive,
+// keyed by slash-separated path within the configuration directory.
+type Backup struct {
+	Files    map[string][]byte
+	Manifest *BackupManifest
+}
+
+// configFiles matches the paths of files Restore will write.
+var configFiles = []*regexp.Regexp{
+	regexp.MustCompile(`^version$`),
+	regexp.MustCompile(`^profile$`),
+	regexp.MustCompile(`^config\.json$`),
+	regexp.MustCompile(`^licensor\.json$`),
+	regexp.MustCompile(`^(profiles/[a-zA-Z0-9][a-zA-Z0-9_-]*/)?(developer|identity)\.json$`),
+	regexp.MustCompile(`^(profiles/[a-zA-Z0-9][a-zA-Z0-9_-]*/)?waivers/[0-9a-f]{64}\.json$`),
+}
+
+func knownConfigFile(name string) bool {
+	for _, pattern := range configFiles {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// backupEntryName strips the configuration directory's name
+// from the path of an archive entry, rejecting paths that
+// would escape the configuration directory.
+func backupEntryName(name string) (string, error) {
+	if strings.HasPrefix(name, "/") || strings.Contains(name, "\\") {
+		return "", errors.New("unsafe path " + name)
+	}
+	parts := strings.Split(strings.TrimSuffix(name, "/"), "/")
+	for _, part := range parts {
+		if part == ".." || part == "." || part == "" {
+			return "", errors.New("unsafe path " + name)
+		}
+	}
+	return strings.Join(parts[1:], "/"), nil
+}
+
+func validateConfigFile(name string, content []byte) error {
+	switch name {
+	case "version":
+		version, err := strconv.Atoi(strings.TrimSpace(string(content)))
+		if err != nil {
+			return errors.New("invalid version")
+		}
+		if version > SchemaVersion {
+			return errors.New("made by a newer version of licensezero")
+		}
+		return nil
+	case "profile":
+		if !ValidProfileName(strings.TrimSpace(string(content))) {
+			return errors.New("invalid profile name")
+		}
+		return nil
+	}
+	var parsed map[string]interface{}
+	err := json.Unmarshal(content, &parsed)
+	if err != nil {
+		return errors.New("invalid JSON")
+	}
+	return nil
+}
+
+// ForProfile returns the backup's files for one profile.
+// Backups made before profiles, or of the default profile,
+// have files for DefaultProfile.
+func (backup *Backup) ForProfile(profile string) *Backup {
+	return backup.moveProfile(profile, profile)
+}
+
+// moveProfile returns the backup's files for profile source,
+// with paths for profile target.
+func (backup *Backup) moveProfile(source, target string) *Backup {
+	sourcePrefix := profilePrefix(source)
+	targetPrefix := profilePrefix(target)
+	selected := Backup{Files: make(map[string][]byte)}
+	for name, content := range backup.Files {
+		if inProfile(name, sourcePrefix) {
+			selected.Files[targetPrefix+name[len(sourcePrefix):]] = content
+		}
+	}
+	return &selected
+}
+
+func profilePrefix(profile string) string {
+	if profile == DefaultProfile {
+		return ""
+	}
+	return "profiles/" + profile + "/"
+}
+
+var profileWaiver = regexp.MustCompile(`^waivers/[0-9a-f]{64}\.json$`)
+
+func inProfile(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	rest := name[len(prefix):]
+	return rest == "developer.json" || rest == "identity.json" || profileWaiver.MatchString(rest)
+}
+
+// RestoreChange describes how restoring a backup changes a file.
+// Current is nil for added files.  Restored is nil for removed files.
+type RestoreChange struct {
+	Path     string
+	Current  []byte
+	Restored []byte
+}
+
+// PlanRestore compares a backup to the configuration directory.
+// If target is not empty, only that profile's files change, to the
+// backup's files for profile source.  Otherwise, the whole backup is
+// restored, and known files missing from the backup are removed.
+func PlanRestore(home string, backup *Backup, source, target string) ([]RestoreChange, error) {
+	current, err := readConfigFiles(home)
+	if err != nil {
+		return nil, err
+	}
+	if target != "" {
+		prefix := profilePrefix(target)
+		for name := range current {
+			if !inProfile(name, prefix) {
+				delete(current, name)
+			}
+		}
+		backup = backup.moveProfile(source, target)
+	}
+	var changes []RestoreChange
+	for name, restored := range backup.Files {
+		existing, ok := current[name]
+		if !ok || !bytes.Equal(existing, restored) {
+			changes = append(changes, RestoreChange{Path: name, Current: existing, Restored: restored})
+		}
+	}
+	for name, existing := range current {
+		if _, ok := backup.Files[name]; !ok {
+			changes = append(changes, RestoreChange{Path: name, Current: existing})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+// readConfigFiles reads known files in the configuration directory.
+func readConfigFiles(home string) (map[string][]byte, error) {
+	root := ConfigPath(home)
+	files := make(map[string][]byte)
+	err := filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && filePath == root {
+				return nil
+			}
+			return err
+		}
+		relative, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(relative)
+		if info.IsDir() {
+			if name != "." && unversioned(name) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || !knownConfigFile(name) {
+			return nil
+		}
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		files[name] = content
+		return nil
+	})
+	return files, err
+}
+
+// Restore applies changes planned by PlanRestore, after copying
+// the configuration directory to BackupPath, while holding the lock
+// on the configuration directory.  It writes every restored file
+// before renaming any into place, and if a change fails, it puts
+// back the files it already changed.  Restore returns the path of
+// the copy of the old configuration.
+func Restore(home string, changes []RestoreChange) (string, error) {
+	unlock, err := lockConfig(home)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	saved := BackupPath(home, time.Now())
+	err = copyDirectory(ConfigPath(home), saved)
+	if err != nil {
+		return "", err
+	}
+	staged := make([]string, len(changes))
+	defer func() {
+		// Does nothing for files renamed into place.
+		for _, temporary := range staged {
+			if temporary != "" {
+				os.Remove(temporary)
+			}
+		}
+	}()
+	for i, change := range changes {
+		if change.Restored == nil {
+			continue
+		}
+		staged[i], err = restoreStage(path.Join(ConfigPath(home), change.Path), change.Restored, 0600)
+		if err != nil {
+			return saved, err
+		}
+	}
+	for i, change := range changes {
+		filePath := path.Join(ConfigPath(home), change.Path)
+		if change.Restored == nil {
+			err = os.Remove(filePath)
+			if os.IsNotExist(err) {
+				err = nil
+			}
+		} else {
+			err = restoreRename(staged[i], filePath)
+		}
+		if err != nil {
+			rollbackErr := rollBack(home, changes[:i])
+			if rollbackErr != nil {
+				return saved, errors.New(err.Error() + ", and could not undo changes: " + rollbackErr.Error())
+			}
+			return saved, err
+		}
+	}
+	return saved, nil
+}
+
+// restoreStage and restoreRename are variables so tests can make them fail.
+var restoreStage = stageFile
+var restoreRename = os.Rename
+
+// rollBack reverses changes Restore already made.
+func rollBack(home string, changes []RestoreChange) error {
+	var firstErr error
+	for _, change := range changes {
+		filePath := path.Join(ConfigPath(home), change.Path)
+		var err error
+		if change.Current == nil {
+			err = os.Remove(filePath)
+			if os.IsNotExist(err) {
+				err = nil
+			}
+		} else {
+			err = writeFile(filePath, change.Current, 0600)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,166 @@
+package data
+
+import "archive/tar"
+import "bytes"
+import "errors"
+import "io/ioutil"
+import "os"
+import "path"
+import "testing"
+
+func makeTar(t *testing.T, files map[string]string) *bytes.Buffer {
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	for name, content := range files {
+		err := writer.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0600,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer.Write([]byte(content))
+	}
+	writer.Close()
+	return &buffer
+}
+
+func TestReadBackup(t *testing.T) {
+	backup, err := ReadBackup(makeTar(t, map[string]string{
+		"licensezero/identity.json":                `{"name":"Test"}`,
+		"licensezero/profiles/work/developer.json": `{"developerID":"x"}`,
+		"licensezero/cache/offering/x.json":        `{}`,
+		"licensezero/version":                      "1\n",
+	}).Bytes(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backup.Files) != 3 {
+		t.Error("wrong files")
+	}
+	if len(backup.ForProfile("work").Files) != 1 {
+		t.Error("wrong profile files")
+	}
+	if len(backup.ForProfile(DefaultProfile).Files) != 1 {
+		t.Error("wrong default profile files")
+	}
+}
+
+func TestPlanRestoreIntoProfile(t *testing.T) {
+	directory, err := ioutil.TempDir("", "licensezero-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	os.Setenv("LICENSEZERO_CONFIG", directory)
+	defer os.Unsetenv("LICENSEZERO_CONFIG")
+	ioutil.WriteFile(path.Join(directory, "identity.json"), []byte(`{"name":"Default"}`), 0600)
+	backup := &Backup{Files: map[string][]byte{
+		"identity.json": []byte(`{"name":"Old"}`),
+	}}
+	changes, err := PlanRestore("", backup, DefaultProfile, "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Path != "profiles/work/identity.json" || changes[0].Current != nil {
+		t.Error("does not restore into target profile", changes)
+	}
+}
+
+func TestRestoreFailure(t *testing.T) {
+	defer func() {
+		restoreStage = stageFile
+		restoreRename = os.Rename
+	}()
+	failures := map[string]func(){
+		"write": func() {
+			calls := 0
+			restoreStage = func(filePath string, data []byte, mode os.FileMode) (string, error) {
+				calls++
+				if calls == 2 {
+					return "", errors.New("disk full")
+				}
+				return stageFile(filePath, data, mode)
+			}
+		},
+		"rename": func() {
+			calls := 0
+			restoreRename = func(from, to string) error {
+				calls++
+				if calls == 2 {
+					return errors.New("disk full")
+				}
+				return os.Rename(from, to)
+			}
+		},
+	}
+	for name, inject := range failures {
+		restoreStage = stageFile
+		restoreRename = os.Rename
+		directory, err := ioutil.TempDir("", "licensezero-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(directory)
+		os.Setenv("LICENSEZERO_CONFIG", directory)
+		defer os.Unsetenv("LICENSEZERO_CONFIG")
+		before := map[string]string{
+			"identity.json":  `{"name":"Current"}`,
+			"developer.json": `{"developerID":"current"}`,
+		}
+		for file, content := range before {
+			ioutil.WriteFile(path.Join(directory, file), []byte(content), 0600)
+		}
+		backup := &Backup{Files: map[string][]byte{
+			"config.json":    []byte(`{"json":true}`),
+			"identity.json":  []byte(`{"name":"Restored"}`),
+			"developer.json": []byte(`{"developerID":"restored"}`),
+		}}
+		changes, err := PlanRestore("", backup, "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		inject()
+		saved, err := Restore("", changes)
+		if saved != "" {
+			defer os.RemoveAll(saved)
+		}
+		if err == nil {
+			t.Fatal(name + " failure did not fail restore")
+		}
+		for file, content := range before {
+			after, _ := ioutil.ReadFile(path.Join(directory, file))
+			if string(after) != content {
+				t.Error(name + " failure changed " + file)
+			}
+		}
+		if _, err := os.Stat(path.Join(directory, "config.json")); !os.IsNotExist(err) {
+			t.Error(name + " failure left config.json")
+		}
+		entries, _ := ioutil.ReadDir(directory)
+		for _, entry := range entries {
+			if entry.Name()[0] == '.' && entry.Name() != ".lock" {
+				t.Error(name + " failure left " + entry.Name())
+			}
+		}
+	}
+}
+
+func TestReadBackupInvalid(t *testing.T) {
+	invalid := []map[string]string{
+		{"licensezero/../identity.json": `{}`},
+		{"/licensezero/identity.json": `{}`},
+		{"licensezero/identity.json": `{`},
+		{"licensezero/unknown.json": `{}`},
+		{"licensezero/profiles/../developer.json": `{}`},
+		{"licensezero/version": "99"},
+	}
+	for _, files := range invalid {
+		_, err := ReadBackup(makeTar(t, files).Bytes(), nil)
+		if err == nil {
+			t.Error("accepted", files)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package data
+
+import "os"
+import "path"
+
+// Settings holds the user's defaults for command options.
+// Options given on the command line override them.
+type Settings struct {
+	DoNotOpen    bool   `json:"doNotOpen,omitempty"`
+	Relicense    uint   `json:"relicense,omitempty"`
+	Jurisdiction string `json:"jurisdiction,omitempty"`
+	Output       string `json:"output,omitempty"`
+}
+
+func settingsPath(home string) string {
+	return path.Join(ConfigPath(home), "config.json")
+}
+
+// ReadSettings reads the user's settings from disk.
+// If there is no settings file, it returns empty Settings.
+func ReadSettings(home string) (*Settings, error) {
+	var settings Settings
+	err := readJSONFile(settingsPath(home), &settings)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// WriteSettings writes the user's settings to disk.
+func WriteSettings(home string, settings *Settings) error {
+	return writeConfigFile(home, settingsPath(home), settings, 0600)
+}
@@ -0,0 +1,138 @@
+package data
+
+import "bytes"
+import "encoding/hex"
+import "encoding/json"
+import "errors"
+import "golang.org/x/crypto/ed25519"
+import "strings"
+import "time"
+
+// Waiver is a signed waiver of a public license's conditions.
+type Waiver struct {
+	Manifest       string `json:"manifest"`
+	Document       string `json:"document"`
+	PublicKey      string `json:"publicKey"`
+	Signature      string `json:"signature"`
+	AgentSignature string `json:"agentSignature"`
+}
+
+// WaiverManifest describes the parties and terms of a Waiver.
+type WaiverManifest struct {
+	Form        string         `json:"FORM"`
+	Version     string         `json:"VERSION"`
+	Date        string         `json:"date"`
+	Term        interface{}    `json:"term"`
+	Beneficiary Party          `json:"beneficiary"`
+	Licensor    Party          `json:"licensor"`
+	Offer       OfferReference `json:"offer"`
+}
+
+// Party describes a party to a license or waiver.
+type Party struct {
+	Name         string `json:"name"`
+	Jurisdiction string `json:"jurisdiction"`
+	EMail        string `json:"email,omitempty"`
+	PublicKey    string `json:"publicKey,omitempty"`
+}
+
+// OfferReference identifies the offer a license or waiver relates to.
+type OfferReference struct {
+	OfferID     string `json:"offerID"`
+	Homepage    string `json:"homepage"`
+	Description string `json:"description"`
+}
+
+// ParseWaiver parses a JSON waiver.
+func ParseWaiver(data []byte) (*Waiver, error) {
+	var waiver Waiver
+	err := json.Unmarshal(data, &waiver)
+	if err != nil {
+		return nil, err
+	}
+	if waiver.Manifest == "" || waiver.Document == "" {
+		return nil, errors.New("missing manifest or document")
+	}
+	return &waiver, nil
+}
+
+// ParseManifest parses the waiver's manifest.
+func (waiver *Waiver) ParseManifest() (*WaiverManifest, error) {
+	var manifest WaiverManifest
+	err := json.Unmarshal([]byte(waiver.Manifest), &manifest)
+	if err != nil {
+		return nil, errors.New("invalid manifest")
+	}
+	if manifest.Form != WaiverForm {
+		return nil, errors.New("not a waiver")
+	}
+	return &manifest, nil
+}
+
+// Expiration returns when the waiver expires.
+// forever is true if the waiver never expires.
+func (manifest *WaiverManifest) Expiration() (expires time.Time, forever bool, err error) {
+	if term, ok := manifest.Term.(string); ok && term == "forever" {
+		return time.Time{}, true, nil
+	}
+	days, ok := manifest.Term.(float64)
+	if !ok || days < 1 {
+		return time.Time{}, false, errors.New("invalid term")
+	}
+	date, err := time.Parse(time.RFC3339, manifest.Date)
+	if err != nil {
+		return time.Time{}, false, errors.New("invalid date")
+	}
+	return date.AddDate(0, 0, int(days)), false, nil
+}
+
+// Verify checks the waiver's signatures by the licensor and the agent,
+// and that its manifest matches its document and names the licensor's key.
+func (waiver *Waiver) Verify(licensorKey, agentKey ed25519.PublicKey) error {
+	manifest, err := waiver.ParseManifest()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(waiver.Document, waiver.Manifest+"\n\n") {
+		return errors.New("document does not match manifest")
+	}
+	if !sameKey(manifest.Licensor.PublicKey, licensorKey) {
+		return errors.New("manifest does not name the licensor's public key")
+	}
+	return verifySignatures(waiver.Document, waiver.PublicKey, waiver.Signature, waiver.AgentSignature, licensorKey, agentKey)
+}
+
+// ParsePublicKey parses a hex-encoded ed25519 public key.
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	decoded, err := hex.DecodeString(encoded)
+	if err != nil || len(decoded) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid public key")
+	}
+	return ed25519.PublicKey(decoded), nil
+}
+
+func sameKey(encoded string, key ed25519.PublicKey) bool {
+	parsed, err := ParsePublicKey(encoded)
+	return err == nil && bytes.Equal(parsed, key)
+}
+
+func verifySignatures(document, publicKey, signature, agentSignature string, licensorKey, agentKey ed25519.PublicKey) error {
+	if !sameKey(publicKey, licensorKey) {
+		return errors.New("public key does not match the licensor's")
+	}
+	if !validSignature(licensorKey, document, signature) {
+		return errors.New("invalid licensor signature")
+	}
+	if !validSignature(agentKey, document, agentSignature) {
+		return errors.New("invalid agent signature")
+	}
+	return nil
+}
+
+func validSignature(key ed25519.PublicKey, document, encoded string) bool {
+	signature, err := hex.DecodeString(encoded)
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return false
+	}
+	return ed25519.Verify(key, []byte(document), signature)
+}
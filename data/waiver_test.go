@@ -0,0 +1,34 @@
+package data
+
+import "testing"
+import "time"
+
+func TestWaiverExpiration(t *testing.T) {
+	manifest := WaiverManifest{Date: "2020-01-01T00:00:00Z", Term: float64(30)}
+	expires, forever, err := manifest.Expiration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forever {
+		t.Error("forever")
+	}
+	if !expires.Equal(time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Error("wrong expiration")
+	}
+}
+
+func TestWaiverForever(t *testing.T) {
+	manifest := WaiverManifest{Date: "2020-01-01T00:00:00Z", Term: "forever"}
+	_, forever, err := manifest.Expiration()
+	if err != nil || !forever {
+		t.Error("not forever")
+	}
+}
+
+func TestWaiverInvalidTerm(t *testing.T) {
+	manifest := WaiverManifest{Date: "2020-01-01T00:00:00Z", Term: "always"}
+	_, _, err := manifest.Expiration()
+	if err == nil {
+		t.Error("accepted invalid term")
+	}
+}
@@ -0,0 +1,115 @@
+package data
+
+import "bytes"
+import "encoding/json"
+import "errors"
+import "io/ioutil"
+import "os"
+import "path"
+import "regexp"
+import "sort"
+import "strings"
+
+// ArchivedWaiver is a waiver stored by ArchiveWaiver.
+// Raw is the waiver as the API returned it.
+type ArchivedWaiver struct {
+	ID       string
+	Waiver   *Waiver
+	Manifest *WaiverManifest
+	Raw      []byte
+}
+
+var archivedWaiverName = regexp.MustCompile(`^[0-9a-f]{64}\.json$`)
+
+func waiversPath(home string) string {
+	return path.Join(activeProfilePath(home), "waivers")
+}
+
+// WaiverID identifies a waiver by the SHA-256 digest of its document.
+func WaiverID(waiver *Waiver) string {
+	return checksum([]byte(waiver.Document))
+}
+
+// ArchiveWaiver stores an issued waiver with the active profile,
+// as body, the API response it was parsed from.
+// It returns the waiver's ID.
+func ArchiveWaiver(home string, waiver *Waiver, body []byte) (string, error) {
+	id := WaiverID(waiver)
+	filePath := path.Join(waiversPath(home), id+".json")
+	return id, writeConfigFile(home, filePath, json.RawMessage(body), 0600)
+}
+
+// ReadWaivers reads the active profile's archived waivers,
+// oldest first.
+func ReadWaivers(home string) ([]ArchivedWaiver, error) {
+	directory := waiversPath(home)
+	entries, err := ioutil.ReadDir(directory)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var waivers []ArchivedWaiver
+	for _, entry := range entries {
+		name := entry.Name()
+		if !archivedWaiverName.MatchString(name) {
+			continue
+		}
+		archived, err := readArchivedWaiver(path.Join(directory, name))
+		if err != nil {
+			return nil, err
+		}
+		waivers = append(waivers, *archived)
+	}
+	sort.SliceStable(waivers, func(i, j int) bool {
+		return waivers[i].Manifest.Date < waivers[j].Manifest.Date
+	})
+	return waivers, nil
+}
+
+func readArchivedWaiver(filePath string) (*ArchivedWaiver, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var waiver Waiver
+	err = json.Unmarshal(content, &waiver)
+	if err != nil {
+		return nil, &MalformedFileError{Path: filePath, Err: err}
+	}
+	manifest, err := waiver.ParseManifest()
+	if err != nil {
+		return nil, &MalformedFileError{Path: filePath, Err: err}
+	}
+	return &ArchivedWaiver{
+		ID:       strings.TrimSuffix(path.Base(filePath), ".json"),
+		Waiver:   &waiver,
+		Manifest: manifest,
+		Raw:      bytes.TrimSpace(content),
+	}, nil
+}
+
+// ErrAmbiguousWaiverID indicates an ID prefix that matches several waivers.
+var ErrAmbiguousWaiverID = errors.New("ambiguous waiver ID")
+
+// ReadWaiver reads an archived waiver by ID or unique ID prefix.
+func ReadWaiver(home, id string) (*ArchivedWaiver, error) {
+	waivers, err := ReadWaivers(home)
+	if err != nil {
+		return nil, err
+	}
+	var found *ArchivedWaiver
+	for i := range waivers {
+		if strings.HasPrefix(waivers[i].ID, strings.ToLower(id)) {
+			if found != nil {
+				return nil, ErrAmbiguousWaiverID
+			}
+			found = &waivers[i]
+		}
+	}
+	if found == nil {
+		return nil, os.ErrNotExist
+	}
+	return found, nil
+}
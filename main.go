@@ -1,10 +1,16 @@
 package main
 
+import "flag"
 import "fmt"
+import "io/ioutil"
+import "log"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/data"
 import "licensezero.com/cli/subcommands"
 import "github.com/mitchellh/go-homedir"
 import "os"
 import "sort"
+import "time"
 
 // Rev represents the current build revision.  Set via ldflags.
 var Rev string
@@ -12,19 +18,29 @@ var Rev string
 var commands = map[string]*subcommands.Subcommand{
 	"backup":   subcommands.Backup,
 	"bugs":     subcommands.Bugs,
+	"cache":    subcommands.Cache,
+	"config":   subcommands.Config,
 	"identify": subcommands.Identify,
+	"init":     subcommands.Init,
 	"latest":   subcommands.Latest,
+	"ledger":   subcommands.Ledger,
 	"lock":     subcommands.Lock,
 	"offer":    subcommands.Offer,
 	"offers":   subcommands.Offers,
+	"profile":  subcommands.Profile,
+	"quote":    subcommands.Quote,
 	"raise":    subcommands.Raise,
 	"register": subcommands.Register,
 	"reprice":  subcommands.Reprice,
 	"reset":    subcommands.Reset,
+	"restore":  subcommands.Restore,
 	"retract":  subcommands.Retract,
+	"stamp":    subcommands.Stamp,
 	"token":    subcommands.Token,
+	"verify":   subcommands.Verify,
 	"version":  subcommands.Version,
 	"freebie":  subcommands.Freebie,
+	"waivers":  subcommands.Waivers,
 	"whoami":   subcommands.WhoAmI,
 }
 
@@ -38,14 +54,48 @@ func main() {
 		subcommands.Fail("Could not find working directory.")
 	}
 	paths := subcommands.Paths{Home: home, CWD: cwd}
-	arguments := os.Args
-	if len(arguments) > 1 {
-		subcommand := os.Args[1]
+	flagSet := flag.NewFlagSet("licensezero", flag.ContinueOnError)
+	apiURL := flagSet.String("api-url", "", "")
+	timeout := flagSet.Duration("timeout", api.DefaultTimeout, "")
+	noCache := flagSet.Bool("no-cache", false, "")
+	refresh := flagSet.Bool("refresh", false, "")
+	verbose := flagSet.Bool("verbose", false, "")
+	profile := flagSet.String("profile", "", "")
+	debug := flagSet.Bool("debug", false, "")
+	flagSet.SetOutput(ioutil.Discard)
+	if flagSet.Parse(os.Args[1:]) != nil {
+		showUsage()
+		os.Exit(1)
+	}
+	if *profile != "" {
+		os.Setenv("LICENSEZERO_PROFILE", *profile)
+	}
+	if !data.ValidProfileName(data.ActiveProfile(home)) {
+		subcommands.Fail("Invalid profile name.")
+	}
+	client := api.NewClient(baseURL(*apiURL), userAgent())
+	client.Timeout = *timeout
+	if !*noCache {
+		client.Cache = &data.ResponseCache{Home: home}
+		client.Refresh = *refresh
+		client.OnStale = warnStale
+	}
+	if *verbose || *debug {
+		client.Logger = log.New(os.Stderr, "licensezero: ", log.Ltime|log.Lmicroseconds)
+		client.LogBodies = *debug
+	}
+	arguments := flagSet.Args()
+	if len(arguments) > 0 {
+		subcommand := arguments[0]
 		if value, ok := commands[subcommand]; ok {
+			// Let `config migrate --dry-run` see pending migrations.
+			if subcommand != "config" {
+				subcommands.Migrate(paths)
+			}
 			if subcommand == "version" || subcommand == "latest" {
-				value.Handler([]string{Rev}, paths)
+				value.Handler([]string{Rev}, paths, client)
 			} else {
-				value.Handler(os.Args[2:], paths)
+				value.Handler(arguments[1:], paths, client)
 			}
 		} else {
 			showUsage()
@@ -57,6 +107,28 @@ func main() {
 	}
 }
 
+func baseURL(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	fromEnvironment := os.Getenv("LICENSEZERO_API")
+	if fromEnvironment != "" {
+		return fromEnvironment
+	}
+	return api.DefaultBaseURL
+}
+
+func userAgent() string {
+	if Rev == "" {
+		return "licensezero-cli/development"
+	}
+	return "licensezero-cli/" + Rev
+}
+
+func warnStale(action, key string, stored time.Time) {
+	os.Stderr.WriteString("Warning: Could not reach the API. Using " + action + " data cached " + stored.Format(time.RFC3339) + ".\n")
+}
+
 func showUsage() {
 	os.Stdout.WriteString("Manage License Zero offers.\n\nSubcommands:\n")
 	longestSubcommand := 0
@@ -73,4 +145,12 @@ func showUsage() {
 		info := commands[name]
 		fmt.Printf("  %-"+fmt.Sprintf("%d", longestSubcommand)+"s %s\n", name, info.Description)
 	}
+	os.Stdout.WriteString("\nGlobal Options:\n")
+	os.Stdout.WriteString("  --api-url URL       API endpoint. Defaults to $LICENSEZERO_API or " + api.DefaultBaseURL + ".\n")
+	os.Stdout.WriteString("  --debug             Like --verbose, plus request and response bodies, with secrets redacted.\n")
+	os.Stdout.WriteString("  --no-cache          Do not read or write cached API responses.\n")
+	os.Stdout.WriteString("  --profile NAME      Use a named profile. Defaults to $LICENSEZERO_PROFILE or the profile in use.\n")
+	os.Stdout.WriteString("  --refresh           Ignore cached API responses, but cache new ones.\n")
+	os.Stdout.WriteString("  --timeout DURATION  Limit on each API request attempt, like \"10s\". Defaults to " + api.DefaultTimeout.String() + ".\n")
+	os.Stdout.WriteString("  --verbose           Log API requests to standard error.\n")
 }
@@ -1,12 +1,20 @@
 package main
 
+import "archive/tar"
 import "bytes"
+import "compress/gzip"
+import "encoding/hex"
+import "encoding/json"
 import "io/ioutil"
-import "net/http"
+import "licensezero.com/cli/api/apitest"
+import "licensezero.com/cli/data"
 import "os"
 import "os/exec"
+import "path"
+import "path/filepath"
 import "strings"
 import "testing"
+import "time"
 
 func TestSanity(t *testing.T) {
 	command := exec.Command("./licensezero")
@@ -95,6 +103,45 @@ func TestWhoAmIWithIdentity(t *testing.T) {
 	})
 }
 
+func TestProfiles(t *testing.T) {
+	InTestDir(t, func() {
+		Run("", "identify", "--name", "Home User", "--jurisdiction", "US-CA", "--email", "home@example.com", "--silent")
+		_, stderr, err := Run("", "--profile", "work", "identify", "--name", "Work User", "--jurisdiction", "US-NY", "--email", "work@example.com", "--silent")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		stdout, _, _ := Run("", "--profile", "work", "whoami")
+		if !strings.Contains(stdout, "Work User") || !strings.Contains(stdout, "Profile: work") {
+			t.Error("does not use named profile")
+		}
+		stdout, _, _ = Run("", "whoami")
+		if !strings.Contains(stdout, "Home User") || !strings.Contains(stdout, "Profile: default") {
+			t.Error("does not use default profile")
+		}
+		Run("", "profile", "use", "work", "--silent")
+		stdout, _, _ = Run("", "whoami")
+		if !strings.Contains(stdout, "Work User") {
+			t.Error("does not switch profiles")
+		}
+		stdout, _, _ = Run("", "profile", "list")
+		if !strings.Contains(stdout, "* work") || !strings.Contains(stdout, "  default") {
+			t.Error("does not list profiles")
+		}
+		_, _, err = Run("y\n", "profile", "remove", "work", "--silent")
+		if err != nil {
+			t.Error(err)
+		}
+		stdout, _, _ = Run("", "whoami")
+		if !strings.Contains(stdout, "Home User") {
+			t.Error("does not fall back to default profile")
+		}
+		_, _, err = Run("", "--profile", "../escape", "whoami")
+		if err == nil {
+			t.Error("accepts invalid profile name")
+		}
+	})
+}
+
 func Identify() {
 	name := "John Doe"
 	email := "test@example.com"
@@ -112,16 +159,1203 @@ func InTestDir(t *testing.T, script func()) {
 	script()
 }
 
-const port = "8888"
+func WithAPIServer(t *testing.T, script func(*apitest.Server, apitest.Developer)) {
+	InTestDir(t, func() {
+		server := apitest.NewServer()
+		defer server.Close()
+		os.Setenv("LICENSEZERO_API", server.URL)
+		defer os.Unsetenv("LICENSEZERO_API")
+		developer := server.AddDeveloper("Jane Dev", "US-CA", "jane@example.com")
+		err := data.WriteDeveloper("", &data.Developer{
+			DeveloperID: developer.DeveloperID,
+			Token:       developer.Token,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		script(server, developer)
+	})
+}
+
+// AgentKey returns the test server's agent key for --agent-key.
+// Without it, freebie and verify check the pinned licensezero.com key.
+func AgentKey(server *apitest.Server) string {
+	return hex.EncodeToString(server.AgentPublicKey)
+}
+
+func Run(input string, arguments ...string) (string, string, error) {
+	return RunIn("", input, arguments...)
+}
 
-func WithDataServer(script func()) {
-	server := http.Server{
-		Addr:    ":" + port,
-		Handler: http.FileServer(http.Dir(".")),
+// RunIn runs the CLI with directory as its working directory.
+func RunIn(directory, input string, arguments ...string) (string, string, error) {
+	binary, err := filepath.Abs("licensezero")
+	if err != nil {
+		return "", "", err
+	}
+	command := exec.Command(binary, arguments...)
+	command.Dir = directory
+	var stdout, stderr bytes.Buffer
+	command.Stdin = strings.NewReader(input)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	err = command.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+func MakeOffer(t *testing.T) string {
+	stdout, stderr, err := Run("y\n", "offer", "--price", "1000", "--repository", "https://example.com/project", "--description", "test project", "--do-not-open")
+	if err != nil {
+		t.Fatal(stderr)
+	}
+	index := strings.Index(stdout, "Offer ID: ")
+	if index == -1 {
+		t.Fatal("does not print offer ID")
+	}
+	return strings.Fields(stdout[index+len("Offer ID: "):])[0]
+}
+
+func TestOffer(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		offer, ok := server.Offer(offerID)
+		if !ok {
+			t.Fatal("offer not created")
+		}
+		if offer.Homepage != "https://example.com/project" {
+			t.Error("wrong homepage")
+		}
+		if offer.Pricing.Private != 1000 {
+			t.Error("wrong price")
+		}
+	})
+}
+
+func TestOfferWithoutAgencyTerms(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		_, _, err := Run("n\n", "offer", "--price", "1000", "--repository", "https://example.com/project", "--description", "test project", "--do-not-open")
+		if err == nil {
+			t.Error("Should fail")
+		}
+		if server.Count("offer") != 0 {
+			t.Error("sent offer request")
+		}
+	})
+}
+
+func TestOffers(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		stdout, stderr, err := Run("", "offers", "--json")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		var parsed []struct {
+			OfferID     string `json:"offerID"`
+			Description string `json:"description"`
+		}
+		err = json.Unmarshal([]byte(stdout), &parsed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(parsed) != 1 || parsed[0].OfferID != offerID {
+			t.Error("does not list offer")
+		} else if parsed[0].Description != "test project" {
+			t.Error("wrong description")
+		}
+	})
+}
+
+func TestOffersPartialFailure(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		var offerIDs []string
+		for i := 0; i < 12; i++ {
+			offerIDs = append(offerIDs, MakeOffer(t))
+		}
+		server.BreakOffering(offerIDs[5], 404)
+		stdout, _, err := Run("", "offers", "--json")
+		if err == nil {
+			t.Error("Should fail")
+		}
+		var parsed []struct {
+			OfferID     string `json:"offerID"`
+			Description string `json:"description"`
+			Error       string `json:"error"`
+		}
+		err = json.Unmarshal([]byte(stdout), &parsed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(parsed) != len(offerIDs) {
+			t.Fatal("does not list all offers")
+		}
+		for i, item := range parsed {
+			if item.OfferID != offerIDs[i] {
+				t.Error("out of order")
+			}
+			if i == 5 {
+				if item.Error == "" {
+					t.Error("does not report error")
+				}
+			} else if item.Description != "test project" {
+				t.Error("missing description")
+			}
+		}
+	})
+}
+
+func TestCacheClear(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		MakeOffer(t)
+		Run("", "offers")
+		Run("", "offers")
+		if server.Count("offering") != 1 {
+			t.Error("did not cache offering")
+		}
+		Run("", "--no-cache", "offers")
+		if server.Count("offering") != 2 {
+			t.Error("used cache with --no-cache")
+		}
+		_, stderr, err := Run("", "cache", "clear", "--silent")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		Run("", "offers")
+		if server.Count("offering") != 3 {
+			t.Error("did not clear cache")
+		}
+	})
+}
+
+func TestReprice(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		_, stderr, err := Run("", "reprice", "--id", offerID, "--price", "2000", "--silent")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		offer, _ := server.Offer(offerID)
+		if offer.Pricing.Private != 2000 {
+			t.Error("did not reprice")
+		}
+	})
+}
+
+func TestLock(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		unlock := time.Now().AddDate(0, 0, 30).UTC().Format(time.RFC3339)
+		_, stderr, err := Run("", "lock", "--id", offerID, "--unlock", unlock, "--silent")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		offer, _ := server.Offer(offerID)
+		if offer.Lock.Unlock != unlock {
+			t.Error("did not lock")
+		}
+		_, _, err = Run("", "retract", "--id", offerID)
+		if err == nil {
+			t.Error("retracted locked offer")
+		}
+	})
+}
+
+func TestDebugRedactsToken(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		unlock := time.Now().AddDate(0, 0, 30).UTC().Format(time.RFC3339)
+		stdout, stderr, err := Run("", "--debug", "lock", "--id", offerID, "--unlock", unlock)
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		if !strings.Contains(stderr, "lock request body") {
+			t.Error("does not log request body")
+		}
+		if strings.Contains(stdout+stderr, developer.Token) {
+			t.Error("prints token")
+		}
+	})
+}
+
+func TestRetract(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		_, stderr, err := Run("", "retract", "--id", offerID, "--silent")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		offer, _ := server.Offer(offerID)
+		if offer.Retracted.IsZero() {
+			t.Error("did not retract")
+		}
+	})
+}
+
+func TestRaise(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		_, stderr, err := Run("", "raise", "--id", offerID, "--commission", "25", "--silent")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		offer, _ := server.Offer(offerID)
+		if offer.Commission != 25 {
+			t.Error("did not raise commission")
+		}
+	})
+}
+
+func TestFreebie(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		stdout, stderr, err := Run("", "freebie", "--id", offerID, "--name", "Sam Sponsor", "--email", "sam@example.com", "--jurisdiction", "US-NY", "--days", "30", "--agent-key", AgentKey(server))
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		if !strings.Contains(stdout, "Sam Sponsor") {
+			t.Error("does not print waiver")
+		}
+		if !strings.Contains(stdout, `"issued":`) {
+			t.Error("drops response fields")
+		}
+	})
+}
+
+func TestFreebieVerifies(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		stdout, _, err := Run("", "freebie", "--id", offerID, "--name", "Sam Sponsor", "--email", "sam@example.com", "--jurisdiction", "US-NY", "--forever", "--agent-key", AgentKey(server))
+		if err != nil {
+			t.Fatal(err)
+		}
+		waiver, err := data.ParseWaiver([]byte(stdout))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = waiver.Verify(developer.PublicKey, server.AgentPublicKey)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestFreebieForged(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		server.ForgeWaivers()
+		stdout, stderr, err := Run("", "freebie", "--id", offerID, "--name", "Sam Sponsor", "--email", "sam@example.com", "--jurisdiction", "US-NY", "--forever", "--agent-key", AgentKey(server))
+		if err == nil {
+			t.Error("Should fail")
+		}
+		if stdout != "" {
+			t.Error("prints forged waiver")
+		}
+		if !strings.Contains(stderr, "invalid licensor signature") {
+			t.Error("does not explain failure")
+		}
+	})
+}
+
+func TestFreebiePinnedKey(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		stdout, _, err := Run("", "freebie", "--id", offerID, "--name", "Sam Sponsor", "--email", "sam@example.com", "--jurisdiction", "US-NY", "--forever")
+		if err == nil {
+			t.Error("accepted waiver signed by another agent key")
+		}
+		if stdout != "" {
+			t.Error("prints waiver")
+		}
+	})
+}
+
+func WriteLicense(t *testing.T, server *apitest.Server, offerID string) string {
+	license, err := server.IssueLicense(offerID, data.Party{Name: "Lee Licensee", Jurisdiction: "US-TX", EMail: "lee@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	marshalled, _ := json.Marshal(license)
+	file, err := ioutil.TempFile("", "license")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Write(marshalled)
+	file.Close()
+	return file.Name()
+}
+
+func TestVerifyLicense(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		file := WriteLicense(t, server, MakeOffer(t))
+		defer os.Remove(file)
+		stdout, stderr, err := Run("", "verify", file, "--json", "--agent-key", AgentKey(server))
+		if err != nil {
+			t.Fatal(stdout + stderr)
+		}
+		var parsed struct {
+			Valid  bool   `json:"valid"`
+			Holder string `json:"holder"`
+		}
+		json.Unmarshal([]byte(stdout), &parsed)
+		if !parsed.Valid {
+			t.Error("not valid")
+		}
+		if parsed.Holder != "Lee Licensee" {
+			t.Error("wrong holder")
+		}
+	})
+}
+
+func TestVerifyTampered(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		file := WriteLicense(t, server, MakeOffer(t))
+		defer os.Remove(file)
+		contents, _ := ioutil.ReadFile(file)
+		ioutil.WriteFile(file, bytes.Replace(contents, []byte("private license to use"), []byte("private license to sell"), 1), 0644)
+		stdout, _, err := Run("", "verify", file, "--agent-key", AgentKey(server))
+		if err == nil {
+			t.Fatal("Should fail")
+		}
+		if err.(*exec.ExitError).ExitCode() != 6 {
+			t.Error("wrong exit status")
+		}
+		if !strings.Contains(stdout, "INVALID") {
+			t.Error("does not say invalid")
+		}
+	})
+}
+
+// VerifyJSON runs verify --json on file and parses its output.
+func VerifyJSON(t *testing.T, server *apitest.Server, file string) (bool, string, int) {
+	stdout, stderr, err := Run("", "verify", file, "--json", "--agent-key", AgentKey(server))
+	status := 0
+	if err != nil {
+		status = err.(*exec.ExitError).ExitCode()
+	}
+	var parsed struct {
+		Valid bool   `json:"valid"`
+		Error string `json:"error"`
+	}
+	err = json.Unmarshal([]byte(stdout), &parsed)
+	if err != nil {
+		t.Fatal("invalid JSON output: " + stdout + stderr)
+	}
+	return parsed.Valid, parsed.Error, status
+}
+
+func TestVerifyTruncatedJSON(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		file := WriteLicense(t, server, MakeOffer(t))
+		defer os.Remove(file)
+		contents, _ := ioutil.ReadFile(file)
+		ioutil.WriteFile(file, contents[:len(contents)/2], 0644)
+		valid, message, status := VerifyJSON(t, server, file)
+		if valid {
+			t.Error("truncated license valid")
+		}
+		if message == "" {
+			t.Error("does not explain")
+		}
+		if status != 6 {
+			t.Error("wrong exit status")
+		}
+	})
+}
+
+func TestVerifyTamperedJSON(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		file := WriteLicense(t, server, MakeOffer(t))
+		defer os.Remove(file)
+		contents, _ := ioutil.ReadFile(file)
+		ioutil.WriteFile(file, bytes.Replace(contents, []byte("private license to use"), []byte("private license to sell"), 1), 0644)
+		valid, message, status := VerifyJSON(t, server, file)
+		if valid {
+			t.Error("tampered license valid")
+		}
+		if message == "" {
+			t.Error("does not explain")
+		}
+		if status != 6 {
+			t.Error("wrong exit status")
+		}
+	})
+}
+
+func TestVerifyPinnedKey(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		file := WriteLicense(t, server, MakeOffer(t))
+		defer os.Remove(file)
+		stdout, _, err := Run("", "verify", file, "--json")
+		if err == nil {
+			t.Error("accepted license signed by another agent key")
+		}
+		if strings.Contains(stdout, `"valid":true`) {
+			t.Error("reports valid")
+		}
+	})
+}
+
+func TestVerifyOffline(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		file := WriteLicense(t, server, MakeOffer(t))
+		defer os.Remove(file)
+		server.Close()
+		_, stderr, err := Run("", "verify", file,
+			"--licensor-key", hex.EncodeToString(developer.PublicKey),
+			"--agent-key", AgentKey(server))
+		if err != nil {
+			t.Error(stderr)
+		}
+	})
+}
+
+func TestEncryptedToken(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		os.Setenv("LICENSEZERO_PASSPHRASE", "correct horse")
+		defer os.Unsetenv("LICENSEZERO_PASSPHRASE")
+		_, stderr, err := Run("", "token", "--encrypt", "--silent")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		stored, err := ioutil.ReadFile(path.Join(os.Getenv("LICENSEZERO_CONFIG"), "developer.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(stored), developer.Token) {
+			t.Error("stores plaintext token")
+		}
+		offerID := MakeOffer(t)
+		os.Setenv("LICENSEZERO_PASSPHRASE", "wrong")
+		_, _, err = Run("", "retract", "--id", offerID, "--silent")
+		if err == nil {
+			t.Error("unlocked with wrong passphrase")
+		}
+	})
+}
+
+func TestConfigMigrate(t *testing.T) {
+	InTestDir(t, func() {
+		directory := os.Getenv("LICENSEZERO_CONFIG")
+		legacy := `{"licensorID":"developer","token":"secret"}`
+		ioutil.WriteFile(path.Join(directory, "licensor.json"), []byte(legacy), 0600)
+		stdout, stderr, err := Run("", "config", "migrate", "--dry-run")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		if !strings.Contains(stdout, "licensor.json") {
+			t.Error("does not list migration")
+		}
+		if _, err := os.Stat(path.Join(directory, "licensor.json")); err != nil {
+			t.Error("dry run migrated")
+		}
+		_, stderr, err = Run("", "config", "migrate")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		backups, _ := filepath.Glob(directory + "-backup-*")
+		for _, backup := range backups {
+			defer os.RemoveAll(backup)
+		}
+		if len(backups) != 1 {
+			t.Error("did not back up")
+		}
+		stdout, _, _ = Run("", "config", "migrate", "--dry-run")
+		if !strings.Contains(stdout, "up to date") {
+			t.Error("did not migrate")
+		}
+	})
+}
+
+func TestRestore(t *testing.T) {
+	InTestDir(t, func() {
+		Identify()
+		_, stderr, err := Run("", "backup")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		tarballs, _ := filepath.Glob("licensezero-backup-*.tar")
+		for _, tarball := range tarballs {
+			defer os.Remove(tarball)
+		}
+		if len(tarballs) != 1 {
+			t.Fatal("did not make one tarball")
+		}
+		Run("y\n", "identify", "--name", "Someone Else", "--jurisdiction", "US-NY", "--email", "else@example.com", "--silent")
+		stdout, stderr, err := Run("y\n", "restore", tarballs[0])
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		backups, _ := filepath.Glob(os.Getenv("LICENSEZERO_CONFIG") + "-backup-*")
+		for _, backup := range backups {
+			defer os.RemoveAll(backup)
+		}
+		if !strings.Contains(stdout, `"Someone Else" -> "John Doe"`) {
+			t.Error("does not show diff")
+		}
+		identity, err := data.ReadIdentity("")
+		if err != nil || identity.Name != "John Doe" {
+			t.Error("did not restore identity")
+		}
+	})
+}
+
+func TestRestoreIntoProfile(t *testing.T) {
+	InTestDir(t, func() {
+		Identify()
+		_, stderr, err := Run("", "backup")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		tarballs, _ := filepath.Glob("licensezero-backup-*.tar")
+		for _, tarball := range tarballs {
+			defer os.Remove(tarball)
+		}
+		if len(tarballs) != 1 {
+			t.Fatal("did not make one tarball")
+		}
+		defer func() {
+			backups, _ := filepath.Glob(os.Getenv("LICENSEZERO_CONFIG") + "-backup-*")
+			for _, backup := range backups {
+				os.RemoveAll(backup)
+			}
+		}()
+		stdout, stderr, err := Run("y\n", "restore", tarballs[0], "--profile", "work")
+		if err != nil {
+			t.Fatal(stdout + stderr)
+		}
+		if !strings.Contains(stdout, "profiles/work/identity.json") {
+			t.Error("does not restore into target profile")
+		}
+		os.Setenv("LICENSEZERO_PROFILE", "work")
+		identity, err := data.ReadIdentity("")
+		os.Unsetenv("LICENSEZERO_PROFILE")
+		if err != nil || identity.Name != "John Doe" {
+			t.Error("did not restore identity into profile")
+		}
+		_, stderr, err = Run("", "restore", tarballs[0], "--profile", "home", "--from-profile", "missing")
+		if err == nil || !strings.Contains(stderr, "no files for profile missing") {
+			t.Error("does not check source profile")
+		}
+	})
+}
+
+func TestBackupFormats(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		Identify()
+		os.Setenv("LICENSEZERO_PASSPHRASE", "correct horse")
+		defer os.Unsetenv("LICENSEZERO_PASSPHRASE")
+		directory := os.Getenv("LICENSEZERO_CONFIG")
+		for _, arguments := range [][]string{
+			{"--format", "zip"},
+			{"--format", "tar.gz", "--encrypt"},
+		} {
+			output := path.Join(directory, "..", path.Base(directory)+"-archive")
+			defer os.Remove(output)
+			_, stderr, err := Run("", append([]string{"backup", "--output", output, "--silent"}, arguments...)...)
+			if err != nil {
+				t.Fatal(stderr)
+			}
+			content, err := ioutil.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			encrypted := arguments[len(arguments)-1] == "--encrypt"
+			if encrypted && bytes.Contains(content, []byte(developer.Token)) {
+				t.Error("encrypted archive contains plaintext token")
+			}
+			stdout, stderr, err := Run("", "restore", output)
+			if err != nil {
+				t.Fatal(stderr)
+			}
+			if !strings.Contains(stdout, "already matches") {
+				t.Error("did not read archive")
+			}
+		}
+	})
+}
+
+func TestEnvironmentCredentials(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		Identify()
+		offerID := MakeOffer(t)
+		data.WriteDeveloper("", &data.Developer{
+			DeveloperID: developer.DeveloperID,
+			Token:       "wrong",
+		})
+		tokenFile := path.Join(os.Getenv("LICENSEZERO_CONFIG"), "token")
+		ioutil.WriteFile(tokenFile, []byte(developer.Token+"\n"), 0600)
+		os.Setenv("LICENSEZERO_DEVELOPER_ID", developer.DeveloperID)
+		defer os.Unsetenv("LICENSEZERO_DEVELOPER_ID")
+		os.Setenv("LICENSEZERO_TOKEN_FILE", tokenFile)
+		defer os.Unsetenv("LICENSEZERO_TOKEN_FILE")
+		stdout, stderr, err := Run("", "whoami")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		if !strings.Contains(stdout, "Credentials: $LICENSEZERO_DEVELOPER_ID and $LICENSEZERO_TOKEN_FILE") {
+			t.Error("does not report credentials source")
+		}
+		_, stderr, err = Run("", "retract", "--id", offerID, "--silent")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		os.Unsetenv("LICENSEZERO_DEVELOPER_ID")
+		_, stderr, err = Run("", "whoami")
+		if err == nil || !strings.Contains(stderr, "require $LICENSEZERO_DEVELOPER_ID") {
+			t.Error("accepts token without developer ID")
+		}
+	})
+}
+
+func TestEncryptIgnoresEnvironmentCredentials(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		developerFile := path.Join(os.Getenv("LICENSEZERO_CONFIG"), "developer.json")
+		os.Remove(developerFile)
+		os.Setenv("LICENSEZERO_DEVELOPER_ID", developer.DeveloperID)
+		defer os.Unsetenv("LICENSEZERO_DEVELOPER_ID")
+		os.Setenv("LICENSEZERO_TOKEN", developer.Token)
+		defer os.Unsetenv("LICENSEZERO_TOKEN")
+		os.Setenv("LICENSEZERO_PASSPHRASE", "correct horse")
+		defer os.Unsetenv("LICENSEZERO_PASSPHRASE")
+		_, _, err := Run("", "token", "--encrypt", "--silent")
+		if err == nil {
+			t.Error("encrypted environment credentials")
+		}
+		if _, err := os.Stat(developerFile); !os.IsNotExist(err) {
+			t.Error("saved environment credentials")
+		}
+	})
+}
+
+func TestConfigSettings(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		for _, arguments := range [][]string{
+			{"config", "set", "output", "json"},
+			{"config", "set", "jurisdiction", "US-NY"},
+			{"config", "set", "relicense", "5000"},
+		} {
+			_, stderr, err := Run("", arguments...)
+			if err != nil {
+				t.Fatal(stderr)
+			}
+		}
+		_, _, err := Run("", "config", "set", "output", "xml")
+		if err == nil {
+			t.Error("accepts invalid value")
+		}
+		stdout, _, _ := Run("", "config", "get", "jurisdiction")
+		if stdout != "US-NY\n" {
+			t.Error("does not get setting")
+		}
+		stdout, _, _ = Run("", "config", "list")
+		if !strings.Contains(stdout, "relicense = 5000") {
+			t.Error("does not list setting")
+		}
+		stdout, stderr, err := Run("", "offers")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		if !strings.HasPrefix(stdout, "[") {
+			t.Error("does not default to JSON output")
+		}
+		stdout, _, _ = Run("", "offers", "--json=false")
+		if strings.HasPrefix(stdout, "[") {
+			t.Error("flag does not override setting")
+		}
+		stdout, stderr, err = Run("", "freebie", "--id", offerID, "--name", "Sam Sponsor", "--email", "sam@example.com", "--days", "30", "--agent-key", AgentKey(server))
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		if !strings.Contains(stdout, "US-NY") {
+			t.Error("does not default jurisdiction")
+		}
+		Run("", "config", "unset", "jurisdiction")
+		_, _, err = Run("", "config", "get", "jurisdiction")
+		if err == nil {
+			t.Error("did not unset setting")
+		}
+	})
+}
+
+func TestLedger(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		repriced := MakeOffer(t)
+		retracted := MakeOffer(t)
+		stdout, stderr, err := Run("", "ledger", "test project")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		if !strings.Contains(stdout, repriced) || !strings.Contains(stdout, retracted) {
+			t.Error("does not list offers")
+		}
+		stdout, _, _ = Run("", "ledger", "no such project")
+		if stdout != "" {
+			t.Error("does not filter by search")
+		}
+		// Cache responses, then change the offers from another machine.
+		_, stderr, err = Run("", "ledger", "--reconcile", "--json")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		elsewhere, err := ioutil.TempDir("/tmp", "licensezero-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(elsewhere)
+		config := os.Getenv("LICENSEZERO_CONFIG")
+		os.Setenv("LICENSEZERO_CONFIG", elsewhere)
+		os.Setenv("LICENSEZERO_DEVELOPER_ID", developer.DeveloperID)
+		os.Setenv("LICENSEZERO_TOKEN", developer.Token)
+		Run("", "reprice", "--id", repriced, "--price", "2000", "--silent")
+		Run("", "retract", "--id", retracted, "--silent")
+		os.Setenv("LICENSEZERO_CONFIG", config)
+		os.Unsetenv("LICENSEZERO_DEVELOPER_ID")
+		os.Unsetenv("LICENSEZERO_TOKEN")
+		if offer, _ := server.Offer(retracted); offer.Retracted.IsZero() {
+			t.Fatal("did not retract elsewhere")
+		}
+		stdout, stderr, err = Run("", "ledger", "--reconcile", "--json")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		var items []struct {
+			OfferID string   `json:"offerID"`
+			Status  string   `json:"status"`
+			Changes []string `json:"changes"`
+		}
+		err = json.Unmarshal([]byte(stdout), &items)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statuses := make(map[string]string)
+		for _, item := range items {
+			statuses[item.OfferID] = item.Status
+		}
+		if statuses[repriced] != "changed" {
+			t.Error("does not report changed price")
+		}
+		if statuses[retracted] != "retracted" {
+			t.Error("does not report retraction")
+		}
+	})
+}
+
+func TestWaivers(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		waiver, stderr, err := Run("", "freebie", "--id", offerID, "--name", "Sam Sponsor", "--email", "sam@example.com", "--jurisdiction", "US-NY", "--days", "30", "--agent-key", AgentKey(server))
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		_, stderr, err = Run("", "freebie", "--id", offerID, "--name", "Pat Patron", "--email", "pat@example.com", "--jurisdiction", "US-CA", "--forever", "--agent-key", AgentKey(server))
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		stdout, _, _ := Run("", "waivers", "--id", offerID)
+		if !strings.Contains(stdout, "Sam Sponsor") || !strings.Contains(stdout, "Pat Patron") {
+			t.Error("does not list waivers")
+		}
+		stdout, _, _ = Run("", "waivers", "--recipient", "pat@")
+		if strings.Contains(stdout, "Sam Sponsor") || !strings.Contains(stdout, "Pat Patron") {
+			t.Error("does not filter by recipient")
+		}
+		stdout, stderr, _ = Run("", "waivers", "--expiring", "31")
+		if !strings.Contains(stdout, "Sam Sponsor") || strings.Contains(stdout, "Pat Patron") {
+			t.Error("does not filter by expiration")
+		}
+		if !strings.Contains(stderr, "1 waiver(s) expire") {
+			t.Error("does not warn about expiring waivers")
+		}
+		stdout, _, _ = Run("", "waivers", "--recipient", "sam", "--json")
+		var items []struct {
+			ID string `json:"id"`
+		}
+		json.Unmarshal([]byte(stdout), &items)
+		if len(items) != 1 {
+			t.Fatal("does not output JSON")
+		}
+		stdout, stderr, err = Run("", "waivers", "show", items[0].ID[:8])
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		if stdout != waiver {
+			t.Error("does not show archived waiver")
+		}
+	})
+}
+
+func TestBadToken(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		data.WriteDeveloper("", &data.Developer{
+			DeveloperID: developer.DeveloperID,
+			Token:       "wrong",
+		})
+		_, stderr, err := Run("", "retract", "--id", offerID)
+		if err == nil {
+			t.Fatal("Should fail")
+		}
+		if err.(*exec.ExitError).ExitCode() != 3 {
+			t.Error("wrong exit status")
+		}
+		if !strings.Contains(stderr, "licensezero reset") {
+			t.Error("does not suggest reset")
+		}
+	})
+}
+
+func TestStamp(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		project, err := ioutil.TempDir("/tmp", "licensezero-project")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(project)
+		packageJSON := path.Join(project, "package.json")
+		err = ioutil.WriteFile(packageJSON, []byte("{\n    \"name\": \"test\",\n    \"version\": \"1.0.0\"\n}\n"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stdout, stderr, err := RunIn(project, "", "stamp", "--id", offerID)
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		if !strings.Contains(stdout, "package.json") {
+			t.Error("does not report package.json")
+		}
+		_, stderr, err = RunIn(project, "", "stamp", "--id", offerID)
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		content, err := ioutil.ReadFile(packageJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(content), "{\n    \"name\": \"test\",\n    \"version\": \"1.0.0\",\n    \"licensezero\": [") {
+			t.Error("does not preserve package.json:\n" + string(content))
+		}
+		offers, err := data.ParseProjectOffers(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(offers) != 1 {
+			t.Fatal("stamped more than once")
+		}
+		if offers[0].OfferID != offerID || offers[0].Pricing.Private != 1000 {
+			t.Error("wrong metadata")
+		}
+		if offers[0].Developer.PublicKey != hex.EncodeToString(developer.PublicKey) {
+			t.Error("wrong public key")
+		}
+	})
+}
+
+func TestStampRetracted(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		_, stderr, err := Run("", "retract", "--id", offerID, "--silent")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		project, err := ioutil.TempDir("/tmp", "licensezero-project")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(project)
+		_, stderr, err = RunIn(project, "", "stamp", "--id", offerID)
+		if err == nil {
+			t.Fatal("stamped retracted offer")
+		}
+		if !strings.Contains(stderr, "retracted") {
+			t.Error("does not report retraction")
+		}
+		if _, err := os.Stat(path.Join(project, data.ProjectMetadataFile)); !os.IsNotExist(err) {
+			t.Error("wrote metadata")
+		}
+	})
+}
+
+func TestInit(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		Identify()
+		project, err := ioutil.TempDir("/tmp", "licensezero-project")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(project)
+		err = ioutil.WriteFile(path.Join(project, "package.json"), []byte("{\n  \"name\": \"test\",\n  \"license\": \"MIT\",\n  \"version\": \"1.0.0\"\n}\n"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stdout, stderr, err := RunIn(project, "prosperity\ny\n", "init", "--repository", "https://example.com/project", "--description", "test project", "--price", "1000", "--no-relicense")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		license, err := ioutil.ReadFile(path.Join(project, "LICENSE"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(license), "# The Prosperity Public License 3.0.0\n\nContributor: John Doe\n\nSource Code: https://example.com/project\n") {
+			t.Error("wrong license header:\n" + string(license))
+		}
+		index := strings.Index(stdout, "Offer ID: ")
+		if index == -1 {
+			t.Fatal("does not print offer ID")
+		}
+		offerID := strings.Fields(stdout[index+len("Offer ID: "):])[0]
+		if _, ok := server.Offer(offerID); !ok {
+			t.Fatal("offer not created")
+		}
+		content, err := ioutil.ReadFile(path.Join(project, "package.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(content), "{\n  \"name\": \"test\",\n  \"license\": \"Prosperity-3.0.0\",\n  \"version\": \"1.0.0\",\n") {
+			t.Error("did not set license in package.json:\n" + string(content))
+		}
+		offers, err := data.ParseProjectOffers(content)
+		if err != nil || len(offers) != 1 || offers[0].OfferID != offerID {
+			t.Error("did not stamp offer")
+		}
+	})
+}
+
+func TestInitWithoutIdentity(t *testing.T) {
+	InTestDir(t, func() {
+		project, err := ioutil.TempDir("/tmp", "licensezero-project")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(project)
+		_, stderr, err := RunIn(project, "", "init", "--license", "parity", "--repository", "https://example.com/project")
+		if err == nil {
+			t.Fatal("initialized without identity")
+		}
+		if !strings.Contains(stderr, "identify") {
+			t.Error("does not suggest identify")
+		}
+	})
+}
+
+// WriteDependency writes an installed npm package with License Zero
+// metadata for offerIDs.
+func WriteDependency(t *testing.T, project, name string, offerIDs ...string) {
+	var offers []data.ProjectOffer
+	for _, offerID := range offerIDs {
+		offers = append(offers, data.ProjectOffer{OfferID: offerID})
+	}
+	content, err := json.Marshal(map[string]interface{}{
+		"name":        name,
+		"version":     "1.0.0",
+		"licensezero": offers,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	directory := path.Join(project, "node_modules", name)
+	err = os.MkdirAll(directory, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(path.Join(directory, "package.json"), content, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		available := MakeOffer(t)
+		retracted := MakeOffer(t)
+		_, stderr, err := Run("", "retract", "--id", retracted, "--silent")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		project, err := ioutil.TempDir("/tmp", "licensezero-project")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(project)
+		WriteDependency(t, project, "first", available)
+		WriteDependency(t, project, "second", available, retracted)
+		invalid := map[string]string{
+			"shape":  `{"name":"shape","version":"1.0.0","licensezero":{"note":"x"}}`,
+			"legacy": `{"name":"legacy","version":"1.0.0","licensezero":[{"license":{"projectID":"` + available + `","name":"Jane Dev"},"licensorSignature":"00","agentSignature":"00"}]}`,
+			"noid":   `{"name":"noid","version":"1.0.0","licensezero":[{"license":{"name":"Jane Dev"}}]}`,
+		}
+		for name, content := range invalid {
+			directory := path.Join(project, "node_modules", name)
+			os.MkdirAll(directory, 0700)
+			err = ioutil.WriteFile(path.Join(directory, "package.json"), []byte(content), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		stdout, stderr, err := RunIn(project, "", "quote", "--json")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		var output struct {
+			Offers []struct {
+				OfferID   string   `json:"offerID"`
+				Packages  []string `json:"packages"`
+				Retracted string   `json:"retracted"`
+			} `json:"offers"`
+			Total   uint     `json:"total"`
+			Skipped []string `json:"skipped"`
+		}
+		err = json.Unmarshal([]byte(stdout), &output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(output.Offers) != 2 {
+			t.Fatal("did not deduplicate offers")
+		}
+		if output.Offers[0].OfferID != available || len(output.Offers[0].Packages) != 3 {
+			t.Error("wrong packages")
+		}
+		if output.Offers[1].Retracted == "" {
+			t.Error("does not flag retracted offer")
+		}
+		if output.Total != 1000 {
+			t.Error("wrong total")
+		}
+		if len(output.Skipped) != 2 || !strings.Contains(strings.Join(output.Skipped, "\n"), "noid") {
+			t.Errorf("skipped %v", output.Skipped)
+		}
+		if server.Count("offering") != 2 {
+			t.Error("requested invalid offer IDs")
+		}
+		stdout, stderr, err = RunIn(project, "", "quote")
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		if !strings.Contains(stdout, "Total: $10.00") {
+			t.Error("does not print total:\n" + stdout)
+		}
+	})
+}
+
+func TestQuoteLockfile(t *testing.T) {
+	WithAPIServer(t, func(server *apitest.Server, developer apitest.Developer) {
+		offerID := MakeOffer(t)
+		project, err := ioutil.TempDir("/tmp", "licensezero-project")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(project)
+		packageJSON, err := json.Marshal(map[string]interface{}{
+			"name":        "dependency",
+			"version":     "1.0.0",
+			"licensezero": []data.ProjectOffer{{OfferID: offerID}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var tarball bytes.Buffer
+		compressed := gzip.NewWriter(&tarball)
+		archive := tar.NewWriter(compressed)
+		archive.WriteHeader(&tar.Header{Name: "package/package.json", Mode: 0644, Size: int64(len(packageJSON))})
+		archive.Write(packageJSON)
+		archive.Close()
+		compressed.Close()
+		tarballs := path.Join(project, "tarballs")
+		os.Mkdir(tarballs, 0700)
+		err = ioutil.WriteFile(path.Join(tarballs, "dependency-1.0.0.tgz"), tarball.Bytes(), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lockfile := `{"lockfileVersion":3,"packages":{"":{},"node_modules/dependency":{"version":"1.0.0","resolved":"https://registry.npmjs.org/dependency/-/dependency-1.0.0.tgz"},"node_modules/missing":{"version":"2.0.0"}}}`
+		err = ioutil.WriteFile(path.Join(project, "package-lock.json"), []byte(lockfile), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stdout, stderr, err := RunIn(project, "", "quote", "--json", "--tarballs", tarballs)
+		if err != nil {
+			t.Fatal(stderr)
+		}
+		var output struct {
+			Offers []struct {
+				OfferID  string   `json:"offerID"`
+				Packages []string `json:"packages"`
+			} `json:"offers"`
+			Total      uint     `json:"total"`
+			Unresolved []string `json:"unresolved"`
+		}
+		err = json.Unmarshal([]byte(stdout), &output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(output.Offers) != 1 || output.Offers[0].OfferID != offerID {
+			t.Fatal("did not find offer in tarball")
+		}
+		if output.Total != 1000 {
+			t.Error("wrong total")
+		}
+		if len(output.Unresolved) != 1 || output.Unresolved[0] != "missing@2.0.0" {
+			t.Error("does not report unresolved package")
+		}
+		if !strings.Contains(stderr, "missing@2.0.0") {
+			t.Error("does not warn about unresolved package")
+		}
+	})
+}
+
+func TestNewerConfig(t *testing.T) {
+	InTestDir(t, func() {
+		Identify()
+		ioutil.WriteFile(path.Join(os.Getenv("LICENSEZERO_CONFIG"), "version"), []byte("99\n"), 0600)
+		for _, args := range [][]string{{"config", "migrate"}, {"config", "list"}, {"whoami"}} {
+			_, stderr, err := Run("", args...)
+			if err == nil {
+				t.Errorf("%v: accepted newer configuration", args)
+			}
+			if !strings.Contains(stderr, "newer version of licensezero") {
+				t.Errorf("%v: wrong message: %s", args, stderr)
+			}
+		}
+	})
+}
+
+func TestXDGConfigFallback(t *testing.T) {
+	home, err := ioutil.TempDir("/tmp", "licensezero-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	defer os.Setenv("LICENSEZERO_CONFIG", os.Getenv("LICENSEZERO_CONFIG"))
+	os.Setenv("HOME", home)
+	old := path.Join(home, ".config", "licensezero")
+	os.Setenv("LICENSEZERO_CONFIG", old)
+	Identify()
+	os.Unsetenv("LICENSEZERO_CONFIG")
+	os.Setenv("XDG_CONFIG_HOME", path.Join(home, "sandbox"))
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+	stdout, stderr, err := Run("", "whoami")
+	if err != nil {
+		t.Fatal(stderr)
+	}
+	if !strings.Contains(stdout, "John Doe") {
+		t.Error("does not read ~/.config")
+	}
+	if _, err := os.Stat(path.Join(old, "identity.json")); err != nil {
+		t.Fatal("moved configuration without asking")
+	}
+	_, stderr, err = Run("n\n", "config", "move")
+	if err != nil {
+		t.Fatal(stderr)
+	}
+	if _, err := os.Stat(path.Join(old, "identity.json")); err != nil {
+		t.Fatal("moved configuration without confirmation")
+	}
+	_, stderr, err = Run("y\n", "config", "move")
+	if err != nil {
+		t.Fatal(stderr)
+	}
+	if _, err := os.Stat(path.Join(home, "sandbox", "licensezero", "identity.json")); err != nil {
+		t.Error("did not move configuration")
 	}
-	go func() {
-		server.ListenAndServe()
-	}()
-	script()
-	server.Shutdown(nil)
 }
@@ -1,22 +1,106 @@
 package subcommands
 
-import "time"
-import "licensezero.com/cli/data"
+import "flag"
 import "github.com/mholt/archiver"
+import "io/ioutil"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/data"
 import "os"
+import "path"
+import "time"
+
+const backupDescription = "Create an archive of your data."
 
-const backupDescription = "Create a tarball of your data."
+var backupFormats = map[string]archiver.Archiver{
+	"tar":    archiver.Tar,
+	"tar.gz": archiver.TarGz,
+	"zip":    archiver.Zip,
+}
 
-// Backup writes a tarball of configuration files to the current directory.
+// Backup writes an archive of configuration files.
 var Backup = &Subcommand{
 	Description: backupDescription,
-	Handler: func(args []string, paths Paths) {
-		now := time.Now()
-		fileName := "licensezero-backup-" + now.Format(time.RFC3339) + ".tar"
-		err := archiver.Tar.Make(fileName, []string{data.ConfigPath(paths.Home)})
+	Handler: func(args []string, paths Paths, client *api.Client) {
+		flagSet := flag.NewFlagSet("backup", flag.ExitOnError)
+		output := flagSet.String("output", "", "")
+		format := flagSet.String("format", "tar", "")
+		encrypt := flagSet.Bool("encrypt", false, "")
+		silent := silentFlag(flagSet)
+		flagSet.SetOutput(ioutil.Discard)
+		flagSet.Usage = backupUsage
+		flagSet.Parse(args)
+		if flagSet.NArg() != 0 {
+			backupUsage()
+		}
+		archive, ok := backupFormats[*format]
+		if !ok {
+			Fail("Invalid format. Use tar, tar.gz, or zip.")
+		}
+		fileName := *output
+		if fileName == "" {
+			fileName = "licensezero-backup-" + time.Now().UTC().Format("20060102T150405Z") + "." + *format
+			if *encrypt {
+				fileName += ".enc"
+			}
+			fileName = path.Join(paths.CWD, fileName)
+		}
+		var passphrase string
+		if *encrypt {
+			passphrase = newPassphrase()
+		}
+		content, err := makeBackup(paths, client, archive, *format, passphrase)
 		if err != nil {
-			Fail("Error creating tarball.")
+			Fail("Error creating archive: " + err.Error())
+		}
+		_, err = data.ReadBackup(content, func() string { return passphrase })
+		if err != nil {
+			Fail("Invalid archive: " + err.Error() + ".")
+		}
+		err = data.WriteBackupFile(fileName, content)
+		if err != nil {
+			Fail("Could not write " + fileName + ".")
+		}
+		if !*silent {
+			os.Stdout.WriteString("Saved backup to " + fileName + ".\n")
 		}
 		os.Exit(0)
 	},
 }
+
+// makeBackup returns an archive of configuration files,
+// sealed with passphrase if it is not empty.
+func makeBackup(paths Paths, client *api.Client, archive archiver.Archiver, format, passphrase string) ([]byte, error) {
+	staging, err := ioutil.TempDir("", "licensezero-backup")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+	staged, err := data.StageBackup(paths.Home, staging, client.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+	archivePath := path.Join(staging, "backup."+format)
+	err = archive.Make(archivePath, []string{staged})
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(archivePath)
+	if err != nil || passphrase == "" {
+		return content, err
+	}
+	return data.SealBackup(content, passphrase)
+}
+
+func backupUsage() {
+	usage := backupDescription + "\n\n" +
+		"Usage:\n" +
+		"  licensezero backup [--output PATH] [--format tar|tar.gz|zip] [--encrypt]\n\n" +
+		"Options:\n" +
+		flagsList(map[string]string{
+			"encrypt":     "Encrypt the archive with a passphrase. Set $LICENSEZERO_PASSPHRASE to skip the prompt.",
+			"format NAME": "Archive format: tar, tar.gz, or zip. Defaults to tar.",
+			"output PATH": "Where to write the archive. Defaults to a dated file in the current directory.",
+			"silent":      silentLine,
+		})
+	Fail(usage)
+}
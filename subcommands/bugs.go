@@ -2,18 +2,20 @@ package subcommands
 
 import "flag"
 import "io/ioutil"
+import "licensezero.com/cli/api"
 
 const bugsDescription = "Open the CLI bug tracker page."
 
 // Bugs opens the CLI tracker bug tracker page.
 var Bugs = &Subcommand{
 	Description: bugsDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		flagSet := flag.NewFlagSet("bugs", flag.ExitOnError)
 		doNotOpen := doNotOpenFlag(flagSet)
 		flagSet.SetOutput(ioutil.Discard)
 		flagSet.Usage = bugsUsage
 		flagSet.Parse(args)
+		applyDoNotOpen(flagSet, doNotOpen, readSettings(paths))
 		openURLAndExit("https://github.com/licensezero/cli/issues", doNotOpen)
 	},
 }
@@ -0,0 +1,43 @@
+package subcommands
+
+import "flag"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/data"
+import "io/ioutil"
+import "os"
+
+const cacheDescription = "Manage cached API responses."
+
+// Cache manages the API response cache.
+var Cache = &Subcommand{
+	Description: cacheDescription,
+	Handler: func(args []string, paths Paths, client *api.Client) {
+		if len(args) == 0 || args[0] != "clear" {
+			cacheUsage()
+		}
+		flagSet := flag.NewFlagSet("cache", flag.ExitOnError)
+		silent := silentFlag(flagSet)
+		flagSet.SetOutput(ioutil.Discard)
+		flagSet.Usage = cacheUsage
+		flagSet.Parse(args[1:])
+		err := data.ClearCache(paths.Home)
+		if err != nil {
+			Fail("Could not clear cache.")
+		}
+		if !*silent {
+			os.Stdout.WriteString("Cleared cached API responses.\n")
+		}
+		os.Exit(0)
+	},
+}
+
+func cacheUsage() {
+	usage := cacheDescription + "\n\n" +
+		"Usage:\n" +
+		"  licensezero cache clear\n\n" +
+		"Options:\n" +
+		flagsList(map[string]string{
+			"silent": silentLine,
+		})
+	Fail(usage)
+}
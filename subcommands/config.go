@@ -0,0 +1,206 @@
+package subcommands
+
+import "flag"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/data"
+import "io/ioutil"
+import "os"
+import "sort"
+import "strconv"
+import "time"
+
+const configDescription = "Manage settings and the configuration directory."
+
+// Config reads and changes settings and migrates the configuration directory.
+var Config = &Subcommand{
+	Description: configDescription,
+	Handler: func(args []string, paths Paths, client *api.Client) {
+		if len(args) == 0 {
+			configUsage()
+		}
+		flagSet := flag.NewFlagSet("config", flag.ExitOnError)
+		dryRun := flagSet.Bool("dry-run", false, "")
+		silent := silentFlag(flagSet)
+		flagSet.SetOutput(ioutil.Discard)
+		flagSet.Usage = configUsage
+		flagSet.Parse(args[1:])
+		if args[0] != "migrate" {
+			// main does not migrate before config commands,
+			// but they must not touch a newer configuration.
+			if _, err := data.PendingMigrations(paths.Home); err == data.ErrNewerConfig {
+				failNewerConfig(paths)
+			}
+		}
+		switch args[0] {
+		case "migrate":
+			if flagSet.NArg() != 0 {
+				configUsage()
+			}
+			if *dryRun {
+				pending, err := data.PendingMigrations(paths.Home)
+				if err == data.ErrNewerConfig {
+					failNewerConfig(paths)
+				}
+				if err != nil {
+					Fail("Could not read configuration version: " + err.Error())
+				}
+				if len(pending) == 0 {
+					os.Stdout.WriteString("Configuration is up to date.\n")
+					os.Exit(0)
+				}
+				os.Stdout.WriteString("Would back up configuration to " + data.BackupPath(paths.Home, time.Now()) + ".\n")
+				for _, migration := range pending {
+					os.Stdout.WriteString(migrationLine(migration) + "\n")
+				}
+				os.Exit(0)
+			}
+			applied := Migrate(paths)
+			if !*silent && len(applied) == 0 {
+				os.Stdout.WriteString("Configuration is up to date.\n")
+			}
+		case "move":
+			if flagSet.NArg() != 0 {
+				configUsage()
+			}
+			old := data.OldConfigPath(paths.Home)
+			if old == "" {
+				Fail("Nothing to move. The configuration directory is " + data.ConfigPath(paths.Home) + ".")
+			}
+			if !confirm("Move configuration from " + old + " to $XDG_CONFIG_HOME?") {
+				os.Exit(0)
+			}
+			moved, err := data.MoveOldConfig(paths.Home)
+			if err != nil {
+				Fail("Could not move configuration: " + err.Error() + ".")
+			}
+			if !*silent {
+				os.Stdout.WriteString("Moved configuration from " + old + " to " + moved + ".\n")
+			}
+		case "list":
+			if flagSet.NArg() != 0 {
+				configUsage()
+			}
+			settings := readSettings(paths)
+			for _, key := range settingNames() {
+				value := settingKeys[key].get(settings)
+				if value != "" {
+					os.Stdout.WriteString(key + " = " + value + "\n")
+				}
+			}
+		case "get":
+			if flagSet.NArg() != 1 {
+				configUsage()
+			}
+			key := settingKey(flagSet.Arg(0))
+			value := key.get(readSettings(paths))
+			if value == "" {
+				os.Exit(1)
+			}
+			os.Stdout.WriteString(value + "\n")
+		case "set":
+			if flagSet.NArg() != 2 {
+				configUsage()
+			}
+			key := settingKey(flagSet.Arg(0))
+			settings := readSettings(paths)
+			err := key.set(settings, flagSet.Arg(1))
+			if err != nil {
+				Fail("Invalid value for " + flagSet.Arg(0) + ": " + err.Error() + ".")
+			}
+			writeSettings(paths, settings)
+		case "unset":
+			if flagSet.NArg() != 1 {
+				configUsage()
+			}
+			key := settingKey(flagSet.Arg(0))
+			settings := readSettings(paths)
+			key.unset(settings)
+			writeSettings(paths, settings)
+		default:
+			configUsage()
+		}
+		os.Exit(0)
+	},
+}
+
+// Migrate applies pending configuration migrations,
+// reporting them on standard error, and fails if one fails.
+func Migrate(paths Paths) []data.Migration {
+	applied, backup, err := data.Migrate(paths.Home)
+	if err == data.ErrNewerConfig {
+		failNewerConfig(paths)
+	}
+	if backup != "" {
+		os.Stderr.WriteString("Backed up configuration to " + backup + ".\n")
+	}
+	for _, migration := range applied {
+		os.Stderr.WriteString(migrationLine(migration) + "\n")
+	}
+	if err != nil {
+		message := "Could not migrate configuration: " + err.Error()
+		if backup != "" {
+			message += "\nRestore the backup from " + backup + "."
+		}
+		Fail(message)
+	}
+	return applied
+}
+
+func failNewerConfig(paths Paths) {
+	Fail("Configuration in " + data.ConfigPath(paths.Home) + " was " + data.ErrNewerConfig.Error() + ".\n" +
+		"Upgrade licensezero to use it.")
+}
+
+func settingNames() []string {
+	var names []string
+	for name := range settingKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func settingKey(name string) setting {
+	key, ok := settingKeys[name]
+	if !ok {
+		Fail("Unknown setting " + name + ". Run `licensezero config` to list settings.")
+	}
+	return key
+}
+
+func writeSettings(paths Paths, settings *data.Settings) {
+	err := data.WriteSettings(paths.Home, settings)
+	if err != nil {
+		Fail("Could not write config.json.")
+	}
+}
+
+func migrationLine(migration data.Migration) string {
+	return "Version " + strconv.Itoa(migration.Version) + ": " + migration.Description
+}
+
+func configUsage() {
+	descriptions := make(map[string]string)
+	for name, key := range settingKeys {
+		descriptions[name] = key.description
+	}
+	usage := configDescription + "\n\n" +
+		"Usage:\n" +
+		"  licensezero config list\n" +
+		"  licensezero config get KEY\n" +
+		"  licensezero config set KEY VALUE\n" +
+		"  licensezero config unset KEY\n" +
+		"  licensezero config migrate [--dry-run]\n" +
+		"  licensezero config move\n\n" +
+		"Options:\n" +
+		flagsList(map[string]string{
+			"dry-run": "List pending migrations without applying them.",
+			"silent":  silentLine,
+		}) + "\n" +
+		"Settings:\n" +
+		settingsList(descriptions) + "\n" +
+		"Options given on the command line override settings.\n\n" +
+		"When $XDG_CONFIG_HOME has no licensezero directory, licensezero uses\n" +
+		"~/.config/licensezero.  `config move` moves it to $XDG_CONFIG_HOME.\n"
+	Fail(usage)
+}
@@ -0,0 +1,67 @@
+package subcommands
+
+import "licensezero.com/cli/data"
+import "os"
+
+// readCredentials reads the developer ID and access token,
+// from the environment or developer.json, without decrypting
+// an encrypted token.  It fails if it cannot.
+func readCredentials(paths Paths) *data.Developer {
+	developer, _, err := data.ReadCredentials(paths.Home)
+	if err != nil {
+		if os.IsNotExist(err) {
+			Fail(developerHint)
+		}
+		if _, malformed := err.(*data.MalformedFileError); malformed {
+			failRead(err, developerHint)
+		}
+		Fail("Could not read credentials: " + err.Error() + ".")
+	}
+	return developer
+}
+
+// readDeveloper reads the developer ID and access token,
+// decrypting an encrypted token with $LICENSEZERO_PASSPHRASE
+// or a passphrase prompt.  It fails if it cannot.
+func readDeveloper(paths Paths) *data.Developer {
+	developer := readCredentials(paths)
+	unlockDeveloper(developer)
+	return developer
+}
+
+// unlockDeveloper decrypts an encrypted token, failing if it cannot.
+func unlockDeveloper(developer *data.Developer) {
+	if !developer.Locked() {
+		return
+	}
+	err := developer.Unlock(passphrase("Passphrase: "))
+	if err != nil {
+		Fail("Could not decrypt your access token: " + err.Error() + ".")
+	}
+}
+
+// newPassphrase returns $LICENSEZERO_PASSPHRASE, or prompts for
+// a new passphrase twice, failing if the entries do not match.
+func newPassphrase() string {
+	fromEnvironment := os.Getenv("LICENSEZERO_PASSPHRASE")
+	if fromEnvironment != "" {
+		return fromEnvironment
+	}
+	first := secretPrompt("New passphrase: ")
+	if first == "" {
+		Fail("Passphrase cannot be empty.")
+	}
+	if secretPrompt("Repeat passphrase: ") != first {
+		Fail("Passphrases do not match.")
+	}
+	return first
+}
+
+// passphrase returns $LICENSEZERO_PASSPHRASE, or prompts for a passphrase.
+func passphrase(prompt string) string {
+	fromEnvironment := os.Getenv("LICENSEZERO_PASSPHRASE")
+	if fromEnvironment != "" {
+		return fromEnvironment
+	}
+	return secretPrompt(prompt)
+}
@@ -0,0 +1,184 @@
+package subcommands
+
+import "encoding/json"
+import "io/ioutil"
+import "licensezero.com/cli/data"
+import "os"
+import "path/filepath"
+import "sort"
+import "strings"
+
+// dependency is a package with License Zero metadata.
+// Invalid describes why its metadata could not be read, if it could not.
+type dependency struct {
+	Name    string              `json:"name"`
+	Version string              `json:"version"`
+	Path    string              `json:"path"`
+	Offers  []data.ProjectOffer `json:"-"`
+	Invalid string              `json:"-"`
+}
+
+// readDependency reads License Zero metadata from package.json content.
+// It returns nil for packages without metadata.
+func readDependency(content []byte, name, version, location string) *dependency {
+	var parsed struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(content, &parsed) != nil {
+		return &dependency{Name: name, Version: version, Path: location, Invalid: "package.json is not valid JSON"}
+	}
+	if parsed.Name != "" {
+		name, version = parsed.Name, parsed.Version
+	}
+	result := dependency{Name: name, Version: version, Path: location}
+	offers, err := data.ParseProjectOffers(content)
+	if err != nil {
+		result.Invalid = err.Error()
+		return &result
+	}
+	if len(offers) == 0 {
+		return nil
+	}
+	result.Offers = offers
+	return &result
+}
+
+// label identifies a dependency in output.
+func (dependency *dependency) label() string {
+	if dependency.Name == "" {
+		return dependency.Path
+	}
+	if dependency.Version == "" {
+		return dependency.Name
+	}
+	return dependency.Name + "@" + dependency.Version
+}
+
+// scanNodeModules finds packages with License Zero metadata in
+// node_modules under root and under the root package's workspaces.
+func scanNodeModules(root string) ([]dependency, error) {
+	scanner := nodeModulesScanner{visited: make(map[string]bool)}
+	directories := []string{root}
+	workspaces, err := npmWorkspaces(root)
+	if err != nil {
+		return nil, err
+	}
+	directories = append(directories, workspaces...)
+	for _, directory := range directories {
+		err := scanner.scan(filepath.Join(directory, "node_modules"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := range scanner.found {
+		relative, err := filepath.Rel(root, scanner.found[i].Path)
+		if err == nil {
+			scanner.found[i].Path = filepath.ToSlash(relative)
+		}
+	}
+	sort.Slice(scanner.found, func(i, j int) bool {
+		return scanner.found[i].Path < scanner.found[j].Path
+	})
+	return scanner.found, nil
+}
+
+type nodeModulesScanner struct {
+	visited map[string]bool
+	found   []dependency
+}
+
+// scan reads each package in a node_modules directory,
+// recursing into nested node_modules directories.
+func (scanner *nodeModulesScanner) scan(directory string) error {
+	entries, err := ioutil.ReadDir(directory)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		if strings.HasPrefix(name, "@") {
+			err = scanner.scan(filepath.Join(directory, name))
+		} else {
+			err = scanner.read(filepath.Join(directory, name))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (scanner *nodeModulesScanner) read(directory string) error {
+	// Workspace packages and package managers like pnpm link
+	// packages, so the same package can appear more than once.
+	real, err := filepath.EvalSymlinks(directory)
+	if err != nil {
+		return nil
+	}
+	if scanner.visited[real] {
+		return nil
+	}
+	scanner.visited[real] = true
+	packagePath := filepath.Join(real, "package.json")
+	content, err := ioutil.ReadFile(packagePath)
+	if err != nil {
+		return nil
+	}
+	found := readDependency(content, "", "", directory)
+	if found != nil {
+		scanner.found = append(scanner.found, *found)
+	}
+	return scanner.scan(filepath.Join(real, "node_modules"))
+}
+
+// npmWorkspaces lists the workspace directories of the package in root,
+// from the "workspaces" property of its package.json.
+func npmWorkspaces(root string) ([]string, error) {
+	packagePath := filepath.Join(root, "package.json")
+	content, err := ioutil.ReadFile(packagePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var parsed struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	err = json.Unmarshal(content, &parsed)
+	if err != nil {
+		return nil, &data.MalformedFileError{Path: packagePath, Err: err}
+	}
+	if parsed.Workspaces == nil {
+		return nil, nil
+	}
+	// Yarn also allows {"packages": [...], "nohoist": [...]}.
+	var patterns []string
+	if json.Unmarshal(parsed.Workspaces, &patterns) != nil {
+		var object struct {
+			Packages []string `json:"packages"`
+		}
+		json.Unmarshal(parsed.Workspaces, &object)
+		patterns = object.Packages
+	}
+	var directories []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				directories = append(directories, match)
+			}
+		}
+	}
+	return directories, nil
+}
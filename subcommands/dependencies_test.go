@@ -0,0 +1,90 @@
+package subcommands
+
+import "io/ioutil"
+import "os"
+import "path/filepath"
+import "testing"
+
+// writeFiles writes files, keyed by slash-separated path, under root.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		filePath := filepath.Join(root, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(filePath), 0700)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(filePath, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestScanNodeModules(t *testing.T) {
+	root, err := ioutil.TempDir("", "licensezero-dependencies")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	metadata := `"licensezero":[{"offerID":"offer","pricing":{"private":100}}]`
+	writeFiles(t, root, map[string]string{
+		"package.json":                                        `{"workspaces":{"packages":["packages/*"]}}`,
+		"node_modules/plain/package.json":                     `{"name":"plain","version":"1.0.0"}`,
+		"node_modules/top/package.json":                       `{"name":"top","version":"1.0.0",` + metadata + `}`,
+		"node_modules/@scope/scoped/package.json":             `{"name":"@scope/scoped","version":"2.0.0",` + metadata + `}`,
+		"node_modules/plain/node_modules/nested/package.json": `{"name":"nested","version":"3.0.0",` + metadata + `}`,
+		"packages/app/package.json":                           `{"name":"app"}`,
+		"packages/app/node_modules/local/package.json":        `{"name":"local","version":"4.0.0",` + metadata + `}`,
+	})
+	// Workspace packages are linked into node_modules.
+	err = os.Symlink(filepath.Join(root, "packages", "app"), filepath.Join(root, "node_modules", "app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := scanNodeModules(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"node_modules/@scope/scoped",
+		"node_modules/plain/node_modules/nested",
+		"node_modules/top",
+		"packages/app/node_modules/local",
+	}
+	if len(found) != len(expected) {
+		t.Fatalf("found %v", found)
+	}
+	for i, path := range expected {
+		if found[i].Path != path {
+			t.Errorf("found %s, expected %s", found[i].Path, path)
+		}
+		if len(found[i].Offers) != 1 || found[i].Offers[0].OfferID != "offer" {
+			t.Errorf("%s: wrong offers", path)
+		}
+	}
+}
+
+func TestScanNodeModulesInvalidMetadata(t *testing.T) {
+	root, err := ioutil.TempDir("", "licensezero-dependencies")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	writeFiles(t, root, map[string]string{
+		"node_modules/bad/package.json":  `{"name":"bad","version":"1.0.0","licensezero":{"note":"x"}}`,
+		"node_modules/good/package.json": `{"name":"good","version":"1.0.0","licensezero":[{"offerID":"offer"}]}`,
+	})
+	found, err := scanNodeModules(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("found %v", found)
+	}
+	if found[0].Name != "bad" || found[0].Invalid == "" {
+		t.Error("did not record invalid metadata")
+	}
+	if found[1].Name != "good" || found[1].Invalid != "" || len(found[1].Offers) != 1 {
+		t.Error("did not read valid metadata")
+	}
+}
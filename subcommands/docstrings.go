@@ -14,7 +14,8 @@ const offerIDLine = "Offer ID (UUID)."
 
 const idLine = "License Zero ID (UUID)."
 
-const developerHint = "Register to sell licenses with `licensezero register`."
+const developerHint = "Register to sell licenses with `licensezero register`.\n" +
+	"Or set $LICENSEZERO_DEVELOPER_ID and $LICENSEZERO_TOKEN or $LICENSEZERO_TOKEN_FILE."
 
 const identityHint = "Create an identity with `licensezero identify`."
 
@@ -23,3 +24,13 @@ const termsHint = "You must agree to the terms of service to register."
 const agencyTermsHint = "You must agree to the agency terms to offer private licenses through licensezero.com."
 
 const silentLine = "Suppress output about success."
+
+const encryptLine = "Encrypt the access token with a passphrase. Set $LICENSEZERO_PASSPHRASE to unlock without a prompt."
+
+const authHint = "Check your developer ID and access token, or request a new token with `licensezero reset`."
+
+const notFoundHint = "Check the ID. List your offers with `licensezero offers`."
+
+const networkHint = "Could not reach the License Zero API. Check your network connection, --api-url, and --timeout."
+
+const agentKeyLine = "Check signatures against this licensezero.com public key instead of the built-in one."
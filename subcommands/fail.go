@@ -1,14 +1,58 @@
 package subcommands
 
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/data"
 import "os"
 import "strings"
 
+// Exit statuses for failed API requests, so scripts can tell failures apart.
+const (
+	apiErrorStatus     = 2
+	authErrorStatus    = 3
+	notFoundStatus     = 4
+	networkErrorStatus = 5
+)
+
+// Exit statuses for licenses and waivers that fail verification.
+const (
+	invalidDocumentStatus = 6
+	expiredStatus         = 7
+)
+
 // Fail prints an error message and calls os.Exit(1).
 func Fail(message string) {
+	failWithStatus(message, 1)
+}
+
+func failWithStatus(message string, status int) {
 	if strings.HasSuffix(message, "\n") {
 		os.Stderr.WriteString(message)
 	} else {
 		os.Stderr.WriteString(message + "\n")
 	}
-	os.Exit(1)
+	os.Exit(status)
+}
+
+// failAPI prints an error message for a failed API request,
+// with a hint if one applies, and exits with a status
+// specific to the kind of failure.
+func failAPI(message string, err error) {
+	message = message + ": " + err.Error()
+	if api.IsAuthError(err) {
+		failWithStatus(message+"\n"+authHint, authErrorStatus)
+	} else if api.IsNotFound(err) {
+		failWithStatus(message+"\n"+notFoundHint, notFoundStatus)
+	} else if api.IsNetworkError(err) {
+		failWithStatus(message+"\n"+networkHint, networkErrorStatus)
+	}
+	failWithStatus(message, apiErrorStatus)
+}
+
+// failRead prints hint for a configuration file that could not be read,
+// or a description of the problem if the file is malformed.
+func failRead(err error, hint string) {
+	if malformed, ok := err.(*data.MalformedFileError); ok {
+		Fail("Could not read configuration: " + malformed.Error() + ".")
+	}
+	Fail(hint)
 }
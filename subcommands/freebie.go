@@ -1,5 +1,7 @@
 package subcommands
 
+import "bytes"
+import "context"
 import "flag"
 import "licensezero.com/cli/api"
 import "licensezero.com/cli/data"
@@ -11,7 +13,7 @@ const freebieDescription = "Generate a waiver."
 // Freebie generates a signed waiver.
 var Freebie = &Subcommand{
 	Description: freebieDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		flagSet := flag.NewFlagSet("freebie", flag.ExitOnError)
 		days := flagSet.Uint("days", 0, "Days.")
 		forever := flagSet.Bool("forever", false, "Forever.")
@@ -20,9 +22,13 @@ var Freebie = &Subcommand{
 		jurisdiction := flagSet.String("jurisdiction", "", "User Jurisdiction.")
 		offerID := offerIDFlag(flagSet)
 		id := idFlag(flagSet)
+		agentKeyFlag := flagSet.String("agent-key", "", "")
 		flagSet.SetOutput(ioutil.Discard)
 		flagSet.Usage = freebieUsage
 		flagSet.Parse(args)
+		if *jurisdiction == "" {
+			*jurisdiction = readSettings(paths).Jurisdiction
+		}
 		if *offerID == "" && *id == "" {
 			freebieUsage()
 		} else if *offerID != "" && *id != "" {
@@ -40,21 +46,33 @@ var Freebie = &Subcommand{
 		if !validID(*id) {
 			invalidID()
 		}
-		developer, err := data.ReadDeveloper(paths.Home)
-		if err != nil {
-			Fail(developerHint)
-		}
+		developer := readDeveloper(paths)
 		var term interface{}
 		if *forever {
 			term = "forever"
 		} else {
 			term = *days
 		}
-		bytes, err := api.Freebie(developer, *id, *name, *jurisdiction, *email, term)
+		waiver, body, err := client.Freebie(context.Background(), developer, *id, *name, *jurisdiction, *email, term)
+		if err != nil {
+			failAPI("Error sending waiver request", err)
+		}
+		licensorKey := fetchDeveloperKey(client, developer.DeveloperID)
+		err = waiver.Verify(licensorKey, agentKey(*agentKeyFlag))
+		if err != nil {
+			Fail("The waiver from the API failed verification: " + err.Error() + "\nNot printing it.")
+		}
+		manifest, _ := waiver.ParseManifest()
+		if manifest.Offer.OfferID != *id {
+			Fail("The waiver from the API is for a different offer.\nNot printing it.")
+		}
+		_, err = data.ArchiveWaiver(paths.Home, waiver, body)
 		if err != nil {
-			Fail("Error sending waiver request: " + err.Error())
+			os.Stderr.WriteString("Warning: Could not archive the waiver: " + err.Error() + "\n")
 		}
-		os.Stdout.Write(bytes)
+		// Print the response as received, with any fields Waiver lacks.
+		os.Stdout.Write(bytes.TrimSpace(body))
+		os.Stdout.WriteString("\n")
 		os.Exit(0)
 	},
 }
@@ -65,6 +83,7 @@ func freebieUsage() {
 		"  licensezero freebie --id ID --name NAME --email EMAIL --jurisdiction CODE (--days DAYS | --forever)\n\n" +
 		"Options:\n" +
 		flagsList(map[string]string{
+			"agent-key HEX":     agentKeyLine,
 			"id ID":             idLine,
 			"name NAME":         "User legal name.",
 			"email EMAIL":       "User e-mail.",
@@ -0,0 +1,16 @@
+package subcommands
+
+import "os/exec"
+import "strings"
+
+// git runs a git command in directory, returning its trimmed output,
+// or the empty string if git fails or is not installed.
+func git(directory string, args ...string) string {
+	command := exec.Command("git", args...)
+	command.Dir = directory
+	output, err := command.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
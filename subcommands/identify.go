@@ -1,6 +1,7 @@
 package subcommands
 
 import "flag"
+import "licensezero.com/cli/api"
 import "licensezero.com/cli/data"
 import "io/ioutil"
 import "os"
@@ -10,7 +11,7 @@ const identifyDescription = "Save your identity information."
 // Identify saves user identification information.
 var Identify = &Subcommand{
 	Description: identifyDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		flagSet := flag.NewFlagSet("identify", flag.ExitOnError)
 		jurisdiction := flagSet.String("jurisdiction", "", "")
 		name := flagSet.String("name", "", "")
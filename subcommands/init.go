@@ -0,0 +1,105 @@
+package subcommands
+
+import "flag"
+import "io/ioutil"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/data"
+import "os"
+import "path"
+
+const initDescription = "Set up a project for License Zero."
+
+// Init writes a public license and optionally offers private licenses.
+var Init = &Subcommand{
+	Description: initDescription,
+	Handler: func(args []string, paths Paths, client *api.Client) {
+		flagSet := flag.NewFlagSet("init", flag.ExitOnError)
+		licenseName := flagSet.String("license", "", "")
+		repository := flagSet.String("repository", "", "")
+		description := flagSet.String("description", "", "")
+		price := priceFlag(flagSet)
+		relicense := relicenseFlag(flagSet)
+		noRelicense := noRelicenseFlag(flagSet)
+		force := flagSet.Bool("force", false, "")
+		silent := silentFlag(flagSet)
+		flagSet.SetOutput(ioutil.Discard)
+		flagSet.Usage = initUsage
+		flagSet.Parse(args)
+		if flagSet.NArg() != 0 {
+			initUsage()
+		}
+		applyRelicense(flagSet, relicense, readSettings(paths))
+		if *noRelicense && *relicense != 0 {
+			initUsage()
+		}
+		if *price == 0 && (flagPassed(flagSet, "relicense") || *noRelicense) {
+			initUsage()
+		}
+		identity, err := data.ReadIdentity(paths.Home)
+		if err != nil {
+			failRead(err, identityHint)
+		}
+		if *licenseName == "" {
+			*licenseName = choose("Public license?", []string{"parity", "prosperity"})
+		}
+		license, ok := publicLicenses[*licenseName]
+		if !ok {
+			Fail("Invalid --license. Use parity or prosperity.")
+		}
+		inferOffer(paths, repository, description)
+		if *repository == "" {
+			Fail("Could not find the source code URL. Use --repository.")
+		}
+		licensePath := path.Join(paths.CWD, "LICENSE")
+		if _, err := os.Stat(licensePath); err == nil && !*force {
+			if !confirm("Overwrite existing LICENSE?") {
+				os.Exit(0)
+			}
+		}
+		err = ioutil.WriteFile(licensePath, []byte(license.Text(identity.Name, *repository)), 0644)
+		if err != nil {
+			Fail("Could not write LICENSE.")
+		}
+		if !*silent {
+			os.Stdout.WriteString("Wrote " + license.Name + " to LICENSE.\n")
+		}
+		updated, err := data.SetPackageLicense(paths.CWD, license.SPDX)
+		if malformed, ok := err.(*data.MalformedFileError); ok {
+			Fail("Could not read package.json: " + malformed.Error() + ".")
+		}
+		if err != nil {
+			Fail("Could not write package.json.")
+		}
+		if updated && !*silent {
+			os.Stdout.WriteString("Set license in package.json to " + license.SPDX + ".\n")
+		}
+		if *price != 0 {
+			offerID := createOffer(paths, client, *repository, *description, *price, *relicense)
+			stampOffer(paths, client, offerID, *silent)
+		}
+		os.Exit(0)
+	},
+}
+
+func initUsage() {
+	usage := initDescription + "\n\n" +
+		"Usage:\n" +
+		"  licensezero init [--license parity|prosperity] [--repository URL]\n" +
+		"                   [--price CENTS (--relicense CENTS | --no-relicense)\n" +
+		"                    [--description TEXT]]\n\n" +
+		"Options:\n" +
+		flagsList(map[string]string{
+			"description TEXT": "Offer description.",
+			"force":            "Overwrite LICENSE without asking.",
+			"license NAME":     "Public license: parity or prosperity. Prompts if not given.",
+			"price CENTS":      priceLine + " Offers private licenses and stamps the offer.",
+			"relicense CENTS":  relicenseLine,
+			"no-relicense":     noRelicenseLine,
+			"repository URL":   "Source code repository URL.",
+			"silent":           silentLine,
+		}) + "\n" +
+		"Writes LICENSE with the name from `licensezero identify`,\n" +
+		"and sets license in package.json, if there is one.\n" +
+		"With --price, also runs `licensezero offer` and `licensezero stamp`.\n"
+	Fail(usage)
+}
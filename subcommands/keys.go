@@ -0,0 +1,62 @@
+package subcommands
+
+import "context"
+import "golang.org/x/crypto/ed25519"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/data"
+
+// fetchDeveloperKey fetches a developer's public signing key,
+// failing if it cannot.
+func fetchDeveloperKey(client *api.Client, developerID string) ed25519.PublicKey {
+	information, _, err := client.Developer(context.Background(), developerID)
+	if err != nil {
+		failAPI("Could not fetch developer public key", err)
+	}
+	key, err := data.ParsePublicKey(information.PublicKey)
+	if err != nil {
+		Fail("Invalid developer public key from API.")
+	}
+	return key
+}
+
+// agentPublicKey is licensezero.com's hex-encoded public signing key.
+// Never fetch it from the API, which may be configured to point
+// anywhere, so a different endpoint cannot vouch for its own waivers.
+// Replace the placeholder with the key licensezero.com publishes.
+const agentPublicKey = "LICENSEZERO_COM_AGENT_PUBLIC_KEY"
+
+// agentKey returns the public key given with --agent-key, if any,
+// else licensezero.com's pinned public key.
+func agentKey(flagValue string) ed25519.PublicKey {
+	if flagValue != "" {
+		return pinnedKey(flagValue, "agent-key")
+	}
+	key, err := data.ParsePublicKey(agentPublicKey)
+	if err != nil {
+		Fail("This build of licensezero has no licensezero.com public key. Use --agent-key.")
+	}
+	return key
+}
+
+// fetchOfferKey fetches the public signing key of the developer
+// who made an offer, failing if it cannot.
+func fetchOfferKey(client *api.Client, offerID string) ed25519.PublicKey {
+	information, err := client.Offering(context.Background(), offerID)
+	if err != nil {
+		failAPI("Could not fetch offer information", err)
+	}
+	key, err := data.ParsePublicKey(information.Developer.PublicKey)
+	if err != nil {
+		Fail("Invalid developer public key from API.")
+	}
+	return key
+}
+
+// pinnedKey parses a public key given on the command line.
+func pinnedKey(encoded, flagName string) ed25519.PublicKey {
+	key, err := data.ParsePublicKey(encoded)
+	if err != nil {
+		Fail("Invalid --" + flagName + ". Must be a hex-encoded ed25519 public key.")
+	}
+	return key
+}
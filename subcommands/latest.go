@@ -3,13 +3,14 @@ package subcommands
 import "io/ioutil"
 import "net/http"
 import "os"
+import "licensezero.com/cli/api"
 
 const latestDescription = "Check for a newer version."
 
 // Latest prints checks the running version against the latest available.
 var Latest = &Subcommand{
 	Description: latestDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		var running string
 		if args[0] == "" {
 			running = "Development Build"
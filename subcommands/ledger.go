@@ -0,0 +1,251 @@
+package subcommands
+
+import "context"
+import "encoding/json"
+import "flag"
+import "io/ioutil"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/data"
+import "os"
+import "strings"
+import "time"
+
+const ledgerDescription = "List offers made from this machine."
+
+// Ledger lists and searches the local ledger of offers,
+// optionally reconciling it with the API.
+var Ledger = &Subcommand{
+	Description: ledgerDescription,
+	Handler: func(args []string, paths Paths, client *api.Client) {
+		flagSet := flag.NewFlagSet("ledger", flag.ExitOnError)
+		all := flagSet.Bool("all", false, "")
+		reconcile := flagSet.Bool("reconcile", false, "")
+		outputJSON := flagSet.Bool("json", false, "")
+		flagSet.SetOutput(ioutil.Discard)
+		flagSet.Usage = ledgerUsage
+		flagSet.Parse(args)
+		search := ""
+		if flagSet.NArg() > 0 {
+			search = flagSet.Arg(0)
+			// Allow flags after the search term.
+			flagSet.Parse(flagSet.Args()[1:])
+			if flagSet.NArg() != 0 {
+				ledgerUsage()
+			}
+		}
+		applyOutput(flagSet, outputJSON, readSettings(paths))
+		entries, err := data.ReadLedger(paths.Home)
+		if err != nil {
+			failRead(err, "Could not read ledger.")
+		}
+		developerID := ""
+		if *reconcile {
+			developerID = readCredentials(paths).DeveloperID
+		} else if !*all {
+			// Without credentials, list every entry.
+			developer, _, err := data.ReadCredentials(paths.Home)
+			if err == nil {
+				developerID = developer.DeveloperID
+			}
+		}
+		var items []ledgerItem
+		for _, entry := range entries {
+			if developerID != "" && entry.DeveloperID != developerID {
+				continue
+			}
+			if search != "" && !entry.Matches(search) {
+				continue
+			}
+			items = append(items, ledgerItem{LedgerEntry: entry})
+		}
+		if *reconcile {
+			// Cached responses would hide changes made elsewhere.
+			client.Refresh = true
+			items = reconcileLedger(client, developerID, items, search)
+		}
+		if *outputJSON {
+			if items == nil {
+				items = []ledgerItem{}
+			}
+			marshalled, err := json.Marshal(items)
+			if err != nil {
+				Fail("Error serializing output.")
+			}
+			os.Stdout.WriteString(string(marshalled) + "\n")
+			os.Exit(0)
+		}
+		for i, item := range items {
+			if i != 0 {
+				os.Stdout.WriteString("\n")
+			}
+			os.Stdout.WriteString("- Offer ID: " + item.OfferID + "\n")
+			if item.Status == ledgerUnrecorded {
+				os.Stdout.WriteString("  Offered:     " + item.Created + "\n")
+				os.Stdout.WriteString("  Status:      Not in ledger\n")
+				continue
+			}
+			os.Stdout.WriteString("  Created:     " + item.Created + "\n")
+			os.Stdout.WriteString("  Homepage:    " + item.Homepage + "\n")
+			os.Stdout.WriteString("  Description: " + item.Description + "\n")
+			os.Stdout.WriteString("  Price:       " + currency(item.Private) + "\n")
+			if item.Relicense != 0 {
+				os.Stdout.WriteString("  Relicense:   " + currency(item.Relicense) + "\n")
+			}
+			os.Stdout.WriteString("  Directory:   " + item.CWD + "\n")
+			if item.Commit != "" {
+				os.Stdout.WriteString("  Commit:      " + item.Commit + "\n")
+			}
+			switch item.Status {
+			case ledgerUnchanged:
+				os.Stdout.WriteString("  Status:      Unchanged\n")
+			case ledgerChanged:
+				os.Stdout.WriteString("  Status:      Changed\n")
+				for _, change := range item.Changes {
+					os.Stdout.WriteString("    " + change + "\n")
+				}
+			case ledgerRetracted:
+				os.Stdout.WriteString("  Status:      Retracted " + item.Retracted + "\n")
+			case ledgerMissing:
+				os.Stdout.WriteString("  Status:      Not found in the API\n")
+			case ledgerUnknown:
+				os.Stdout.WriteString("  Status:      Unknown: " + item.Changes[0] + "\n")
+			}
+		}
+		os.Exit(0)
+	},
+}
+
+// Statuses of ledger entries reconciled with the API.
+const (
+	ledgerUnchanged  = "unchanged"
+	ledgerChanged    = "changed"
+	ledgerRetracted  = "retracted"
+	ledgerMissing    = "missing"
+	ledgerUnknown    = "unknown"
+	ledgerUnrecorded = "unrecorded"
+)
+
+type ledgerItem struct {
+	data.LedgerEntry
+	Status    string   `json:"status,omitempty"`
+	Retracted string   `json:"retracted,omitempty"`
+	Changes   []string `json:"changes,omitempty"`
+}
+
+// reconcileLedger compares ledger entries to the developer's offers,
+// adding offers made elsewhere that match search.
+func reconcileLedger(client *api.Client, developerID string, items []ledgerItem, search string) []ledgerItem {
+	_, offers, err := client.Developer(context.Background(), developerID)
+	if err != nil {
+		failAPI("Could not fetch developer information", err)
+	}
+	listed := make(map[string]api.OfferInformation)
+	for _, offer := range offers {
+		listed[offer.OfferID] = offer
+	}
+	var found []api.OfferInformation
+	for _, item := range items {
+		if offer, ok := listed[item.OfferID]; ok && offer.Retracted == "" {
+			found = append(found, offer)
+		}
+	}
+	results := fetchOfferings(client, found)
+	offerings := make(map[string]offeringResult)
+	for i, offer := range found {
+		offerings[offer.OfferID] = results[i]
+	}
+	recorded := make(map[string]bool)
+	for i := range items {
+		item := &items[i]
+		recorded[item.OfferID] = true
+		offer, ok := listed[item.OfferID]
+		if !ok {
+			item.Status = ledgerMissing
+			continue
+		}
+		if offer.Retracted != "" {
+			item.Status = ledgerRetracted
+			item.Retracted = offer.Retracted
+			continue
+		}
+		result := offerings[item.OfferID]
+		if result.err != nil {
+			item.Status = ledgerUnknown
+			item.Changes = []string{result.err.Error()}
+			continue
+		}
+		item.Changes = ledgerChanges(&item.LedgerEntry, result.info)
+		if len(item.Changes) == 0 {
+			item.Status = ledgerUnchanged
+		} else {
+			item.Status = ledgerChanged
+		}
+	}
+	for _, offer := range offers {
+		if recorded[offer.OfferID] {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(offer.OfferID), strings.ToLower(search)) {
+			continue
+		}
+		items = append(items, ledgerItem{
+			LedgerEntry: data.LedgerEntry{
+				OfferID:     offer.OfferID,
+				DeveloperID: developerID,
+				Created:     offer.Offered,
+			},
+			Status:    ledgerUnrecorded,
+			Retracted: offer.Retracted,
+		})
+	}
+	return items
+}
+
+func ledgerChanges(entry *data.LedgerEntry, offering *api.OfferingResponse) []string {
+	var changes []string
+	if offering.Homepage != entry.Homepage {
+		changes = append(changes, "Homepage: "+entry.Homepage+" -> "+offering.Homepage)
+	}
+	if offering.Description != entry.Description {
+		changes = append(changes, "Description: "+entry.Description+" -> "+offering.Description)
+	}
+	if offering.Pricing.Private != entry.Private {
+		changes = append(changes, "Price: "+currency(entry.Private)+" -> "+currency(offering.Pricing.Private))
+	}
+	if offering.Pricing.Relicense != entry.Relicense {
+		changes = append(changes, "Relicense: "+currency(entry.Relicense)+" -> "+currency(offering.Pricing.Relicense))
+	}
+	return changes
+}
+
+// recordOffer adds an offer to the ledger, warning if it cannot.
+func recordOffer(paths Paths, developerID, offerID, homepage, description string, private, relicense uint) {
+	err := data.AppendLedger(paths.Home, &data.LedgerEntry{
+		OfferID:     offerID,
+		DeveloperID: developerID,
+		Homepage:    api.NormalizeHomepage(homepage),
+		Description: description,
+		Private:     private,
+		Relicense:   relicense,
+		Created:     time.Now().UTC().Format(time.RFC3339),
+		CWD:         paths.CWD,
+		Commit:      git(paths.CWD, "rev-parse", "HEAD"),
+	})
+	if err != nil {
+		os.Stderr.WriteString("Warning: Could not record the offer in your ledger: " + err.Error() + "\n")
+	}
+}
+
+func ledgerUsage() {
+	usage := ledgerDescription + "\n\n" +
+		"Usage:\n" +
+		"  licensezero ledger [SEARCH] [--all] [--reconcile] [--json]\n\n" +
+		"Options:\n" +
+		flagsList(map[string]string{
+			"all":       "List offers by every developer, not just yours. Ignored with --reconcile.",
+			"json":      "Output JSON.",
+			"reconcile": "Compare with offers in the API, to find offers changed, retracted, or made elsewhere.",
+		}) + "\n" +
+		"SEARCH matches offer IDs, homepages, descriptions, directories, and commits.\n"
+	Fail(usage)
+}
@@ -1,8 +1,8 @@
 package subcommands
 
+import "context"
 import "flag"
 import "licensezero.com/cli/api"
-import "licensezero.com/cli/data"
 import "io/ioutil"
 import "os"
 
@@ -11,7 +11,7 @@ const lockDescription = "Lock pricing and availability."
 // Lock fixes pricing and availability.
 var Lock = &Subcommand{
 	Description: lockDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		flagSet := flag.NewFlagSet("lock", flag.ExitOnError)
 		offerID := offerIDFlag(flagSet)
 		id := idFlag(flagSet)
@@ -35,13 +35,10 @@ var Lock = &Subcommand{
 		if !validID(*id) {
 			invalidID()
 		}
-		developer, err := data.ReadDeveloper(paths.Home)
+		developer := readDeveloper(paths)
+		err := client.Lock(context.Background(), developer, *id, *unlock)
 		if err != nil {
-			Fail(developerHint)
-		}
-		err = api.Lock(developer, *id, *unlock)
-		if err != nil {
-			Fail("Error sending lock request: " + err.Error())
+			failAPI("Error sending lock request", err)
 		}
 		if !*silent {
 			os.Stdout.WriteString("Locked pricing.\n")
@@ -0,0 +1,301 @@
+package subcommands
+
+import "encoding/json"
+import "io/ioutil"
+import "os"
+import "path/filepath"
+import "sort"
+import "strings"
+
+// lockedPackage is a package resolved in a lockfile.
+type lockedPackage struct {
+	Name      string
+	Version   string
+	Resolved  string
+	Integrity string
+}
+
+// lockfileParsers read the lockfiles of npm, Yarn, and pnpm.
+var lockfileParsers = []struct {
+	file  string
+	parse func(content []byte) ([]lockedPackage, error)
+}{
+	{"npm-shrinkwrap.json", parsePackageLock},
+	{"package-lock.json", parsePackageLock},
+	{"yarn.lock", parseYarnLock},
+	{"pnpm-lock.yaml", parsePnpmLock},
+}
+
+// readLockfiles returns the packages in every lockfile in directory,
+// once per name and version.
+func readLockfiles(directory string) ([]lockedPackage, error) {
+	var packages []lockedPackage
+	seen := make(map[string]bool)
+	for _, parser := range lockfileParsers {
+		content, err := ioutil.ReadFile(filepath.Join(directory, parser.file))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := parser.parse(content)
+		if err != nil {
+			return nil, &lockfileError{file: parser.file, err: err}
+		}
+		for _, locked := range parsed {
+			key := locked.Name + "@" + locked.Version
+			if locked.Name == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			packages = append(packages, locked)
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name != packages[j].Name {
+			return packages[i].Name < packages[j].Name
+		}
+		return packages[i].Version < packages[j].Version
+	})
+	return packages, nil
+}
+
+type lockfileError struct {
+	file string
+	err  error
+}
+
+func (err *lockfileError) Error() string {
+	return err.file + " is invalid: " + err.err.Error()
+}
+
+type packageLockDependency struct {
+	Version      string                           `json:"version"`
+	Resolved     string                           `json:"resolved"`
+	Integrity    string                           `json:"integrity"`
+	Dependencies map[string]packageLockDependency `json:"dependencies"`
+}
+
+// parsePackageLock reads package-lock.json and npm-shrinkwrap.json.
+// Version 2 and 3 lockfiles list packages by path.  Version 1
+// lockfiles nest dependencies.
+func parsePackageLock(content []byte) ([]lockedPackage, error) {
+	var parsed struct {
+		Packages map[string]struct {
+			Name      string `json:"name"`
+			Version   string `json:"version"`
+			Resolved  string `json:"resolved"`
+			Integrity string `json:"integrity"`
+			Link      bool   `json:"link"`
+		} `json:"packages"`
+		Dependencies map[string]packageLockDependency `json:"dependencies"`
+	}
+	err := json.Unmarshal(content, &parsed)
+	if err != nil {
+		return nil, err
+	}
+	var packages []lockedPackage
+	if len(parsed.Packages) != 0 {
+		for key, entry := range parsed.Packages {
+			index := strings.LastIndex(key, "node_modules/")
+			// Skip the root package, workspace sources, and links.
+			if index == -1 || entry.Link {
+				continue
+			}
+			name := entry.Name
+			if name == "" {
+				name = key[index+len("node_modules/"):]
+			}
+			packages = append(packages, lockedPackage{
+				Name:      name,
+				Version:   entry.Version,
+				Resolved:  entry.Resolved,
+				Integrity: entry.Integrity,
+			})
+		}
+		return packages, nil
+	}
+	var walk func(map[string]packageLockDependency)
+	walk = func(dependencies map[string]packageLockDependency) {
+		for name, entry := range dependencies {
+			packages = append(packages, lockedPackage{
+				Name:      name,
+				Version:   entry.Version,
+				Resolved:  entry.Resolved,
+				Integrity: entry.Integrity,
+			})
+			walk(entry.Dependencies)
+		}
+	}
+	walk(parsed.Dependencies)
+	return packages, nil
+}
+
+// parseYarnLock reads yarn.lock, in both the Yarn 1 format
+// and the YAML format of later versions.
+func parseYarnLock(content []byte) ([]lockedPackage, error) {
+	var packages []lockedPackage
+	var current *lockedPackage
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			current = nil
+			if !strings.HasSuffix(line, ":") {
+				continue
+			}
+			specifier := strings.Split(strings.TrimSuffix(line, ":"), ",")[0]
+			name := packageName(strings.Trim(strings.TrimSpace(specifier), `"`))
+			if name == "" || strings.HasPrefix(name, "__") {
+				continue
+			}
+			packages = append(packages, lockedPackage{Name: name})
+			current = &packages[len(packages)-1]
+			continue
+		}
+		// Skip nested properties, like dependencies.
+		if current == nil || strings.HasPrefix(line, "    ") {
+			continue
+		}
+		key, value := yarnField(strings.TrimSpace(line))
+		switch key {
+		case "version":
+			current.Version = value
+		case "resolved":
+			current.Resolved = value
+		case "integrity":
+			current.Integrity = value
+		}
+	}
+	var installed []lockedPackage
+	for _, locked := range packages {
+		// Workspace packages in newer Yarn lockfiles.
+		if !strings.Contains(locked.Version, "use.local") {
+			installed = append(installed, locked)
+		}
+	}
+	return installed, nil
+}
+
+// yarnField splits a line like `key "value"` or `key: value`.
+func yarnField(line string) (string, string) {
+	index := strings.Index(line, " ")
+	if index == -1 {
+		return strings.TrimSuffix(line, ":"), ""
+	}
+	return strings.TrimSuffix(line[:index], ":"), strings.Trim(strings.TrimSpace(line[index+1:]), `"'`)
+}
+
+// packageName returns the name in a specifier like name@range
+// or @scope/name@range.
+func packageName(specifier string) string {
+	if specifier == "" {
+		return ""
+	}
+	index := strings.Index(specifier[1:], "@")
+	if index == -1 {
+		return specifier
+	}
+	return specifier[:index+1]
+}
+
+// parsePnpmLock reads the packages section of pnpm-lock.yaml.
+// Keys look like /name/1.0.0_peer in lockfile version 5,
+// /name@1.0.0(peer) in version 6, and name@1.0.0 in version 9.
+func parsePnpmLock(content []byte) ([]lockedPackage, error) {
+	var packages []lockedPackage
+	var current *lockedPackage
+	slashKeys := false
+	inPackages := false
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			current = nil
+			inPackages = line == "packages:"
+			if strings.HasPrefix(line, "lockfileVersion:") {
+				version := strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "lockfileVersion:")), `'"`)
+				slashKeys = strings.HasPrefix(version, "5")
+			}
+			continue
+		}
+		if !inPackages {
+			continue
+		}
+		if !strings.HasPrefix(line, "   ") {
+			current = nil
+			key := strings.Trim(strings.TrimSuffix(strings.TrimSpace(line), ":"), `'"`)
+			locked := pnpmPackage(key, slashKeys)
+			if locked.Name == "" {
+				continue
+			}
+			packages = append(packages, locked)
+			current = &packages[len(packages)-1]
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if current == nil || !strings.HasPrefix(trimmed, "resolution:") {
+			continue
+		}
+		resolution := strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmed, "resolution:")), "{}")
+		for _, field := range strings.Split(resolution, ",") {
+			key, value := yarnField(strings.TrimSpace(field))
+			switch key {
+			case "integrity":
+				current.Integrity = value
+			case "tarball":
+				current.Resolved = value
+			}
+		}
+	}
+	return packages, nil
+}
+
+func pnpmPackage(key string, slashKeys bool) lockedPackage {
+	key = strings.TrimPrefix(key, "/")
+	if slashKeys {
+		parts := strings.Split(key, "/")
+		if strings.HasPrefix(key, "@") && len(parts) >= 3 {
+			return lockedPackage{Name: parts[0] + "/" + parts[1], Version: strings.Split(parts[2], "_")[0]}
+		} else if len(parts) >= 2 {
+			return lockedPackage{Name: parts[0], Version: strings.Split(parts[1], "_")[0]}
+		}
+		return lockedPackage{}
+	}
+	key = strings.Split(key, "(")[0]
+	name := packageName(key)
+	if name == key {
+		return lockedPackage{}
+	}
+	return lockedPackage{Name: name, Version: key[len(name)+1:]}
+}
+
+// scanLockfiles finds packages with License Zero metadata among the
+// packages in lockfiles in root, without reading node_modules.
+// It also returns the packages it could not find in stores.
+func scanLockfiles(root string, stores *packageStores) ([]dependency, []string, error) {
+	packages, err := readLockfiles(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	var found []dependency
+	var unresolved []string
+	for i := range packages {
+		locked := &packages[i]
+		content, source, err := stores.find(locked)
+		if err != nil {
+			unresolved = append(unresolved, locked.Name+"@"+locked.Version)
+			continue
+		}
+		result := readDependency(content, locked.Name, locked.Version, source)
+		if result != nil {
+			found = append(found, *result)
+		}
+	}
+	return found, unresolved, nil
+}
@@ -0,0 +1,145 @@
+package subcommands
+
+import "archive/tar"
+import "bytes"
+import "compress/gzip"
+import "crypto/sha512"
+import "encoding/base64"
+import "encoding/hex"
+import "io/ioutil"
+import "os"
+import "path/filepath"
+import "reflect"
+import "sort"
+import "testing"
+
+func lockedNames(packages []lockedPackage) []string {
+	var names []string
+	for _, locked := range packages {
+		names = append(names, locked.Name+"@"+locked.Version)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestParseLockfiles(t *testing.T) {
+	cases := []struct {
+		parse    func([]byte) ([]lockedPackage, error)
+		content  string
+		expected []string
+	}{
+		{
+			parsePackageLock,
+			`{"lockfileVersion":3,"packages":{"":{"name":"root"},"node_modules/a":{"version":"1.0.0"},"node_modules/a/node_modules/@scope/b":{"version":"2.0.0"},"node_modules/app":{"link":true},"packages/app":{"version":"0.0.0"}}}`,
+			[]string{"@scope/b@2.0.0", "a@1.0.0"},
+		},
+		{
+			parsePackageLock,
+			`{"lockfileVersion":1,"dependencies":{"a":{"version":"1.0.0","dependencies":{"@scope/b":{"version":"2.0.0"}}}}}`,
+			[]string{"@scope/b@2.0.0", "a@1.0.0"},
+		},
+		{
+			parseYarnLock,
+			"# yarn lockfile v1\n\n\"@scope/b@^2.0.0\", \"@scope/b@^2.0.1\":\n  version \"2.0.0\"\n  resolved \"https://registry.yarnpkg.com/@scope/b/-/b-2.0.0.tgz#abc\"\n  integrity sha512-AAAA\n  dependencies:\n    a \"^1.0.0\"\n\na@^1.0.0:\n  version \"1.0.0\"\n",
+			[]string{"@scope/b@2.0.0", "a@1.0.0"},
+		},
+		{
+			parseYarnLock,
+			"__metadata:\n  version: 6\n\n\"@scope/b@npm:^2.0.0\":\n  version: 2.0.0\n  resolution: \"@scope/b@npm:2.0.0\"\n\n\"app@workspace:packages/app\":\n  version: 0.0.0-use.local\n\n\"a@npm:^1.0.0\":\n  version: 1.0.0\n",
+			[]string{"@scope/b@2.0.0", "a@1.0.0"},
+		},
+		{
+			parsePnpmLock,
+			"lockfileVersion: 5.4\n\nspecifiers:\n  a: ^1.0.0\n\npackages:\n\n  /a/1.0.0:\n    resolution: {integrity: sha512-AAAA}\n    dev: false\n\n  /@scope/b/2.0.0_a@1.0.0:\n    resolution: {integrity: sha512-BBBB}\n",
+			[]string{"@scope/b@2.0.0", "a@1.0.0"},
+		},
+		{
+			parsePnpmLock,
+			"lockfileVersion: '9.0'\n\nimporters:\n\n  .:\n    dependencies:\n      a:\n        specifier: ^1.0.0\n\npackages:\n\n  a@1.0.0:\n    resolution: {integrity: sha512-AAAA}\n\n  '@scope/b@2.0.0':\n    resolution: {integrity: sha512-BBBB, tarball: file:b.tgz}\n\nsnapshots:\n\n  '@scope/b@2.0.0(a@1.0.0)': {}\n",
+			[]string{"@scope/b@2.0.0", "a@1.0.0"},
+		},
+	}
+	for i, testCase := range cases {
+		packages, err := testCase.parse([]byte(testCase.content))
+		if err != nil {
+			t.Errorf("case %d: %s", i, err)
+			continue
+		}
+		if names := lockedNames(packages); !reflect.DeepEqual(names, testCase.expected) {
+			t.Errorf("case %d: got %v", i, names)
+		}
+	}
+}
+
+func makeTarball(t *testing.T, packageJSON string) []byte {
+	var buffer bytes.Buffer
+	compressed := gzip.NewWriter(&buffer)
+	archive := tar.NewWriter(compressed)
+	err := archive.WriteHeader(&tar.Header{Name: "package/package.json", Mode: 0644, Size: int64(len(packageJSON))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive.Write([]byte(packageJSON))
+	archive.Close()
+	compressed.Close()
+	return buffer.Bytes()
+}
+
+func integrity(content []byte) (string, string) {
+	sum := sha512.Sum512(content)
+	return "sha512-" + base64.StdEncoding.EncodeToString(sum[:]), hex.EncodeToString(sum[:])
+}
+
+func TestPackageStores(t *testing.T) {
+	root, err := ioutil.TempDir("", "licensezero-stores")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	metadata := `,"licensezero":[{"offerID":"offer","pricing":{"private":100}}]}`
+	tarball := makeTarball(t, `{"name":"@scope/tarball","version":"1.0.0"`+metadata)
+	tarballIntegrity, _ := integrity(tarball)
+	cached := makeTarball(t, `{"name":"cached","version":"1.0.0"`+metadata)
+	cachedIntegrity, cachedDigest := integrity(cached)
+	pnpmPackageJSON := `{"name":"pnpm","version":"1.0.0"` + metadata
+	pnpmIntegrity, pnpmDigest := integrity([]byte("pnpm package"))
+	fileIntegrity, fileDigest := integrity([]byte(pnpmPackageJSON))
+	writeFiles(t, root, map[string]string{
+		"tarballs/scope-tarball-1.0.0.tgz": string(tarball),
+		"npm/content-v2/sha512/" + cachedDigest[:2] + "/" + cachedDigest[2:4] + "/" + cachedDigest[4:]: string(cached),
+		"pnpm/files/" + pnpmDigest[:2] + "/" + pnpmDigest[2:] + "-index.json":                          `{"files":{"package.json":{"integrity":"` + fileIntegrity + `"}}}`,
+		"pnpm/files/" + fileDigest[:2] + "/" + fileDigest[2:]:                                          pnpmPackageJSON,
+		"yarn/npm-yarn-1.0.0-abc-integrity/node_modules/yarn/package.json":                             `{"name":"yarn","version":"1.0.0"` + metadata,
+		"local/local.tgz": string(makeTarball(t, `{"name":"local","version":"1.0.0"`+metadata)),
+	})
+	stores := packageStores{
+		root:      root,
+		tarballs:  []string{filepath.Join(root, "tarballs")},
+		npmCaches: []string{filepath.Join(root, "npm")},
+		pnpm:      []string{filepath.Join(root, "pnpm")},
+		yarn:      []string{filepath.Join(root, "yarn")},
+	}
+	packages := []lockedPackage{
+		{Name: "@scope/tarball", Version: "1.0.0", Integrity: tarballIntegrity},
+		{Name: "cached", Version: "1.0.0", Integrity: cachedIntegrity},
+		{Name: "pnpm", Version: "1.0.0", Integrity: pnpmIntegrity},
+		{Name: "yarn", Version: "1.0.0"},
+		{Name: "local", Version: "1.0.0", Resolved: "file:local/local.tgz"},
+	}
+	for _, locked := range packages {
+		content, _, err := stores.find(&locked)
+		if err != nil {
+			t.Errorf("%s: %s", locked.Name, err)
+			continue
+		}
+		expected := `"name":"` + locked.Name + `"`
+		if !bytes.Contains(content, []byte(expected)) {
+			t.Errorf("%s: wrong package.json", locked.Name)
+		}
+	}
+	otherIntegrity, _ := integrity([]byte("other"))
+	mismatched := lockedPackage{Name: "@scope/tarball", Version: "1.0.0", Integrity: otherIntegrity}
+	if _, _, err := stores.find(&mismatched); err == nil {
+		t.Error("accepted tarball that does not match integrity")
+	}
+}
@@ -1,8 +1,8 @@
 package subcommands
 
+import "context"
 import "flag"
 import "licensezero.com/cli/api"
-import "licensezero.com/cli/data"
 import "io/ioutil"
 import "os"
 
@@ -11,7 +11,7 @@ const offerDescription = "Offer private licenses for sale."
 // Offer creates an offer and offers private licenses for sale.
 var Offer = &Subcommand{
 	Description: offerDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		flagSet := flag.NewFlagSet("offer", flag.ExitOnError)
 		relicense := relicenseFlag(flagSet)
 		noRelicense := flagSet.Bool("no-relicense", false, "")
@@ -19,37 +19,71 @@ var Offer = &Subcommand{
 		description := flagSet.String("description", "", "")
 		doNotOpen := doNotOpenFlag(flagSet)
 		price := priceFlag(flagSet)
+		stamp := flagSet.Bool("stamp", false, "")
 		flagSet.SetOutput(ioutil.Discard)
 		flagSet.Usage = offerUsage
 		flagSet.Parse(args)
+		settings := readSettings(paths)
+		applyDoNotOpen(flagSet, doNotOpen, settings)
+		applyRelicense(flagSet, relicense, settings)
+		inferOffer(paths, repository, description)
 		if *price == 0 || *repository == "" {
 			offerUsage()
 		}
 		if *noRelicense && *relicense != 0 {
 			offerUsage()
 		}
-		developer, err := data.ReadDeveloper(paths.Home)
-		if err != nil {
-			Fail(developerHint)
-		}
-		if !confirmAgencyTerms() {
-			Fail(agencyTermsHint)
-		}
-		offerID, err := api.Offer(developer, *repository, *description, *price, *relicense)
-		if err != nil {
-			Fail("Error sending offer request: " + err.Error())
-		}
+		offerID := createOffer(paths, client, *repository, *description, *price, *relicense)
 		location := "https://licensezero.com/offers/" + offerID
-		os.Stdout.WriteString("Offer ID: " + offerID + "\n")
+		if *stamp {
+			stampOffer(paths, client, offerID, false)
+		}
 		openURLAndExit(location, doNotOpen)
 	},
 }
 
+// inferOffer fills in a missing repository or description
+// from project files in the working directory.
+func inferOffer(paths Paths, repository, description *string) {
+	if *repository != "" && *description != "" {
+		return
+	}
+	inferred := inferProject(paths.CWD)
+	if inferred == nil {
+		return
+	}
+	os.Stdout.WriteString("Read from " + inferred.Source + ":\n")
+	if *repository == "" && inferred.Homepage != "" {
+		*repository = inferred.Homepage
+		os.Stdout.WriteString("  Homepage:    " + inferred.Homepage + "\n")
+	}
+	if *description == "" && inferred.Description != "" {
+		*description = inferred.Description
+		os.Stdout.WriteString("  Description: " + inferred.Description + "\n")
+	}
+}
+
+// createOffer confirms the agency terms, sends an offer request,
+// records the new offer in the ledger, and prints its ID.
+func createOffer(paths Paths, client *api.Client, repository, description string, price, relicense uint) string {
+	developer := readDeveloper(paths)
+	if !confirmAgencyTerms() {
+		Fail(agencyTermsHint)
+	}
+	offerID, err := client.Offer(context.Background(), developer, repository, description, price, relicense)
+	if err != nil {
+		failAPI("Error sending offer request", err)
+	}
+	recordOffer(paths, developer.DeveloperID, offerID, repository, description, price, relicense)
+	os.Stdout.WriteString("Offer ID: " + offerID + "\n")
+	return offerID
+}
+
 func offerUsage() {
 	usage := offerDescription + "\n\n" +
 		"Usage:\n" +
 		"  licensezero offer --price CENTS (--relicense CENTS || --no-relicense)\\\n" +
-		"                    --repository URL --description TEXT\n\n" +
+		"                    [--repository URL] [--description TEXT] [--stamp]\n\n" +
 		"Options:\n" +
 		flagsList(map[string]string{
 			"description TEXT": "Description.",
@@ -58,6 +92,9 @@ func offerUsage() {
 			"price CENTS":      priceLine,
 			"relicense CENTS":  relicenseLine,
 			"no-relicense":     noRelicenseLine,
-		})
+			"stamp":            "Record the offer in project metadata, like `licensezero stamp`.",
+		}) + "\n" +
+		"Without --repository or --description, offer reads them from package.json,\n" +
+		"go.mod and the git remote, Cargo.toml, pyproject.toml, or composer.json.\n"
 	Fail(usage)
 }
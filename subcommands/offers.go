@@ -1,31 +1,30 @@
 package subcommands
 
+import "context"
 import "encoding/json"
 import "flag"
 import "licensezero.com/cli/api"
-import "licensezero.com/cli/data"
 import "io/ioutil"
 import "os"
+import "sync"
 
 const projectsDescription = "List your projects."
 
 // Offers prints the developer's projects.
 var Offers = &Subcommand{
 	Description: projectsDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		flagSet := flag.NewFlagSet("projects", flag.ExitOnError)
 		retracted := flagSet.Bool("include-retracted", false, "")
 		outputJSON := flagSet.Bool("json", false, "")
 		flagSet.SetOutput(ioutil.Discard)
 		flagSet.Usage = projectsUsage
 		flagSet.Parse(args)
-		developer, err := data.ReadDeveloper(paths.Home)
+		applyOutput(flagSet, outputJSON, readSettings(paths))
+		developer := readCredentials(paths)
+		_, projects, err := client.Developer(context.Background(), developer.DeveloperID)
 		if err != nil {
-			Fail(developerHint)
-		}
-		_, projects, err := api.Developer(developer.DeveloperID)
-		if err != nil {
-			Fail("Could not fetch developer information: " + err.Error())
+			failAPI("Could not fetch developer information", err)
 		}
 		var filtered []api.OfferInformation
 		if *retracted {
@@ -46,23 +45,33 @@ var Offers = &Subcommand{
 			Pricing     api.Pricing         `json:"pricing"`
 			Lock        api.LockInformation `json:"lock"`
 			Commission  uint                `json:"commission"`
+			Error       string              `json:"error,omitempty"`
 		}
 		var output []outputItem
-		for _, project := range filtered {
-			info, err := api.Offering(project.OfferID)
-			if err != nil {
-				Fail("Error fetching info for offer:" + project.OfferID)
+		failed := false
+		offeringResults := fetchOfferings(client, filtered)
+		for i, project := range filtered {
+			item := outputItem{
+				OfferID:   project.OfferID,
+				Offered:   project.Offered,
+				Retracted: project.Retracted,
 			}
-			output = append(output, outputItem{
-				OfferID:     project.OfferID,
-				Offered:     project.Offered,
-				Retracted:   project.Retracted,
-				Pricing:     info.Pricing,
-				Homepage:    info.Homepage,
-				Description: info.Description,
-				Lock:        info.Lock,
-				Commission:  info.Commission,
-			})
+			result := offeringResults[i]
+			if result.err != nil {
+				item.Error = result.err.Error()
+				failed = true
+			} else {
+				item.Pricing = result.info.Pricing
+				item.Homepage = result.info.Homepage
+				item.Description = result.info.Description
+				item.Lock = result.info.Lock
+				item.Commission = result.info.Commission
+			}
+			output = append(output, item)
+		}
+		exitStatus := 0
+		if failed {
+			exitStatus = apiErrorStatus
 		}
 		if *outputJSON {
 			marshalled, err := json.Marshal(output)
@@ -70,7 +79,7 @@ var Offers = &Subcommand{
 				Fail("Error serializing output.")
 			}
 			os.Stdout.WriteString(string(marshalled) + "\n")
-			os.Exit(0)
+			os.Exit(exitStatus)
 		}
 		for i, item := range output {
 			if i != 0 {
@@ -81,6 +90,10 @@ var Offers = &Subcommand{
 			if item.Retracted != "" {
 				os.Stdout.WriteString("  Retracted:  " + item.Offered + "\n")
 			}
+			if item.Error != "" {
+				os.Stdout.WriteString("  Error: " + item.Error + "\n")
+				continue
+			}
 			os.Stdout.WriteString("  Homepage: " + item.Homepage + "\n")
 			os.Stdout.WriteString("  Description: " + item.Description + "\n")
 			os.Stdout.WriteString("  Pricing:\n")
@@ -93,10 +106,42 @@ var Offers = &Subcommand{
 			}
 			os.Stdout.WriteString("  Commission: " + commission(item.Commission) + "\n")
 		}
-		os.Exit(0)
+		os.Exit(exitStatus)
 	},
 }
 
+// offeringWorkers limits concurrent offering requests.
+const offeringWorkers = 8
+
+type offeringResult struct {
+	info *api.OfferingResponse
+	err  error
+}
+
+// fetchOfferings requests information on offers concurrently,
+// returning results in the same order as offers.
+func fetchOfferings(client *api.Client, offers []api.OfferInformation) []offeringResult {
+	results := make([]offeringResult, len(offers))
+	indices := make(chan int)
+	var waitGroup sync.WaitGroup
+	for worker := 0; worker < offeringWorkers && worker < len(offers); worker++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for index := range indices {
+				info, err := client.Offering(context.Background(), offers[index].OfferID)
+				results[index] = offeringResult{info: info, err: err}
+			}
+		}()
+	}
+	for index := range offers {
+		indices <- index
+	}
+	close(indices)
+	waitGroup.Wait()
+	return results
+}
+
 func projectsUsage() {
 	usage := projectsDescription + "\n\n" +
 		"Usage:\n" +
@@ -0,0 +1,102 @@
+package subcommands
+
+import "flag"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/data"
+import "io/ioutil"
+import "os"
+
+const profileDescription = "Manage named developer profiles."
+
+// Profile lists, switches, and removes profiles.
+var Profile = &Subcommand{
+	Description: profileDescription,
+	Handler: func(args []string, paths Paths, client *api.Client) {
+		if len(args) == 0 {
+			profileUsage()
+		}
+		flagSet := flag.NewFlagSet("profile", flag.ExitOnError)
+		silent := silentFlag(flagSet)
+		flagSet.SetOutput(ioutil.Discard)
+		flagSet.Usage = profileUsage
+		flagSet.Parse(args[1:])
+		switch args[0] {
+		case "list":
+			if flagSet.NArg() != 0 {
+				profileUsage()
+			}
+			names, err := data.ListProfiles(paths.Home)
+			if err != nil {
+				Fail("Could not list profiles.")
+			}
+			active := data.ActiveProfile(paths.Home)
+			for _, name := range names {
+				if name == active {
+					os.Stdout.WriteString("* " + name + "\n")
+				} else {
+					os.Stdout.WriteString("  " + name + "\n")
+				}
+			}
+		case "use":
+			name := profileName(flagSet)
+			err := data.UseProfile(paths.Home, name)
+			if err != nil {
+				Fail("Could not switch profiles.")
+			}
+			if !*silent {
+				os.Stdout.WriteString("Using profile " + name + ".\n")
+			}
+		case "remove":
+			name := profileName(flagSet)
+			if name == data.DefaultProfile {
+				Fail("Cannot remove the default profile.")
+			}
+			if !data.ProfileExists(paths.Home, name) {
+				Fail("No such profile.")
+			}
+			if !confirm("Remove profile " + name + " and its access token?") {
+				os.Exit(0)
+			}
+			err := data.RemoveProfile(paths.Home, name)
+			if err != nil {
+				Fail("Could not remove profile.")
+			}
+			if !*silent {
+				os.Stdout.WriteString("Removed profile " + name + ".\n")
+			}
+		default:
+			profileUsage()
+		}
+		os.Exit(0)
+	},
+}
+
+func profileName(flagSet *flag.FlagSet) string {
+	if flagSet.NArg() < 1 {
+		profileUsage()
+	}
+	name := flagSet.Arg(0)
+	// Allow flags after the profile name.
+	flagSet.Parse(flagSet.Args()[1:])
+	if flagSet.NArg() != 0 {
+		profileUsage()
+	}
+	if !data.ValidProfileName(name) {
+		Fail("Invalid profile name. Use letters, numbers, dashes, and underscores.")
+	}
+	return name
+}
+
+func profileUsage() {
+	usage := profileDescription + "\n\n" +
+		"Usage:\n" +
+		"  licensezero profile list\n" +
+		"  licensezero profile use NAME\n" +
+		"  licensezero profile remove NAME\n\n" +
+		"Options:\n" +
+		flagsList(map[string]string{
+			"silent": silentLine,
+		}) + "\n" +
+		"Use `licensezero --profile NAME COMMAND` to run one command with a profile.\n"
+	Fail(usage)
+}
@@ -0,0 +1,213 @@
+package subcommands
+
+import "encoding/json"
+import "io/ioutil"
+import "path"
+import "regexp"
+import "strings"
+
+// projectMetadata describes a project in the working directory.
+type projectMetadata struct {
+	Source      string
+	Homepage    string
+	Description string
+}
+
+// projectReaders read metadata from project files, in order of preference.
+var projectReaders = []struct {
+	file string
+	read func(directory string, content []byte) *projectMetadata
+}{
+	{"package.json", readPackageJSON},
+	{"go.mod", readGoMod},
+	{"Cargo.toml", readCargoToml},
+	{"pyproject.toml", readPyprojectToml},
+	{"composer.json", readComposerJSON},
+}
+
+// inferProject reads metadata from the first project file found in
+// directory, falling back to the git remote for the homepage.
+// It returns nil if there is no project file.
+func inferProject(directory string) *projectMetadata {
+	for _, reader := range projectReaders {
+		content, err := ioutil.ReadFile(path.Join(directory, reader.file))
+		if err != nil {
+			continue
+		}
+		metadata := reader.read(directory, content)
+		if metadata == nil {
+			continue
+		}
+		metadata.Source = reader.file
+		if metadata.Homepage == "" {
+			metadata.Homepage = gitRemoteURL(directory)
+		}
+		return metadata
+	}
+	return nil
+}
+
+func readPackageJSON(directory string, content []byte) *projectMetadata {
+	var parsed struct {
+		Homepage    string          `json:"homepage"`
+		Description string          `json:"description"`
+		Repository  json.RawMessage `json:"repository"`
+	}
+	if json.Unmarshal(content, &parsed) != nil {
+		return nil
+	}
+	homepage := parsed.Homepage
+	if homepage == "" && len(parsed.Repository) > 0 {
+		// repository is either a string or an object with a url.
+		var repository string
+		var object struct {
+			URL string `json:"url"`
+		}
+		if json.Unmarshal(parsed.Repository, &repository) != nil {
+			json.Unmarshal(parsed.Repository, &object)
+			repository = object.URL
+		}
+		homepage = repositoryURL(repository)
+	}
+	return &projectMetadata{Homepage: homepage, Description: parsed.Description}
+}
+
+func readComposerJSON(directory string, content []byte) *projectMetadata {
+	var parsed struct {
+		Homepage    string `json:"homepage"`
+		Description string `json:"description"`
+		Support     struct {
+			Source string `json:"source"`
+		} `json:"support"`
+	}
+	if json.Unmarshal(content, &parsed) != nil {
+		return nil
+	}
+	homepage := parsed.Homepage
+	if homepage == "" {
+		homepage = parsed.Support.Source
+	}
+	return &projectMetadata{Homepage: homepage, Description: parsed.Description}
+}
+
+var goModule = regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)"?`)
+
+// readGoMod uses the git remote for the homepage, or the module path
+// if it names a well-known host.  go.mod has no description.
+func readGoMod(directory string, content []byte) *projectMetadata {
+	match := goModule.FindSubmatch(content)
+	if match == nil {
+		return nil
+	}
+	homepage := gitRemoteURL(directory)
+	module := string(match[1])
+	if homepage == "" && (strings.HasPrefix(module, "github.com/") || strings.HasPrefix(module, "gitlab.com/")) {
+		homepage = "https://" + module
+	}
+	return &projectMetadata{Homepage: homepage}
+}
+
+func readCargoToml(directory string, content []byte) *projectMetadata {
+	values := tomlStrings(content)
+	homepage := values["package.homepage"]
+	if homepage == "" {
+		homepage = values["package.repository"]
+	}
+	return &projectMetadata{Homepage: homepage, Description: values["package.description"]}
+}
+
+func readPyprojectToml(directory string, content []byte) *projectMetadata {
+	values := tomlStrings(content)
+	metadata := projectMetadata{Description: values["project.description"]}
+	for _, key := range []string{"project.urls.Homepage", "project.urls.homepage", "project.urls.Repository", "project.urls.repository"} {
+		if values[key] != "" {
+			metadata.Homepage = values[key]
+			break
+		}
+	}
+	// Poetry keeps metadata in its own table.
+	if metadata.Homepage == "" {
+		metadata.Homepage = values["tool.poetry.homepage"]
+	}
+	if metadata.Homepage == "" {
+		metadata.Homepage = values["tool.poetry.repository"]
+	}
+	if metadata.Description == "" {
+		metadata.Description = values["tool.poetry.description"]
+	}
+	return &metadata
+}
+
+var tomlTable = regexp.MustCompile(`^\[([A-Za-z0-9_.-]+)\]$`)
+var tomlString = regexp.MustCompile(`^([A-Za-z0-9_-]+|"[^"]*")\s*=\s*("((?:[^"\\]|\\.)*)"|'([^']*)')\s*(#.*)?$`)
+
+// tomlStrings reads single-line string values from TOML,
+// keyed by table and key, like "package.description".
+// It is not a complete TOML parser, but covers project metadata.
+func tomlStrings(content []byte) map[string]string {
+	values := make(map[string]string)
+	table := ""
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if match := tomlTable.FindStringSubmatch(line); match != nil {
+			table = match[1]
+			continue
+		}
+		match := tomlString.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		key := strings.Trim(match[1], `"`)
+		if table != "" {
+			key = table + "." + key
+		}
+		if strings.HasPrefix(match[2], "'") {
+			values[key] = match[4]
+		} else {
+			var unquoted string
+			if json.Unmarshal([]byte(match[2]), &unquoted) == nil {
+				values[key] = unquoted
+			}
+		}
+	}
+	return values
+}
+
+// gitRemoteURL returns a web URL for the origin remote, if any.
+func gitRemoteURL(directory string) string {
+	return repositoryURL(git(directory, "remote", "get-url", "origin"))
+}
+
+var scpLikeRemote = regexp.MustCompile(`^[^@/:]+@([^:/]+):(.+)$`)
+var shorthandRepository = regexp.MustCompile(`^(github:|gitlab:|bitbucket:)?([A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)$`)
+
+// repositoryURL converts a git remote or npm repository to a web URL.
+func repositoryURL(repository string) string {
+	repository = strings.TrimSpace(repository)
+	if repository == "" {
+		return ""
+	}
+	if match := shorthandRepository.FindStringSubmatch(repository); match != nil {
+		host := "github.com"
+		switch match[1] {
+		case "gitlab:":
+			host = "gitlab.com"
+		case "bitbucket:":
+			host = "bitbucket.org"
+		}
+		return "https://" + host + "/" + match[2]
+	}
+	if match := scpLikeRemote.FindStringSubmatch(repository); match != nil {
+		repository = "https://" + match[1] + "/" + match[2]
+	}
+	repository = strings.TrimPrefix(repository, "git+")
+	for _, prefix := range []string{"ssh://git@", "git://", "ssh://"} {
+		if strings.HasPrefix(repository, prefix) {
+			repository = "https://" + strings.TrimPrefix(repository, prefix)
+		}
+	}
+	if !strings.HasPrefix(repository, "https://") && !strings.HasPrefix(repository, "http://") {
+		return ""
+	}
+	return strings.TrimSuffix(repository, ".git")
+}
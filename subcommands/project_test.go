@@ -0,0 +1,53 @@
+package subcommands
+
+import "io/ioutil"
+import "os"
+import "path"
+import "testing"
+
+func TestRepositoryURL(t *testing.T) {
+	cases := map[string]string{
+		"git@github.com:example/project.git":         "https://github.com/example/project",
+		"git+https://github.com/example/project.git": "https://github.com/example/project",
+		"github:example/project":                     "https://github.com/example/project",
+		"example/project":                            "https://github.com/example/project",
+		"gitlab:example/project":                     "https://gitlab.com/example/project",
+		"ssh://git@gitlab.com/example/project.git":   "https://gitlab.com/example/project",
+		"https://example.com/project":                "https://example.com/project",
+		"/some/local/path":                           "",
+	}
+	for input, expected := range cases {
+		if repositoryURL(input) != expected {
+			t.Errorf("%s: got %q", input, repositoryURL(input))
+		}
+	}
+}
+
+func TestInferProject(t *testing.T) {
+	files := map[string]string{
+		"package.json":   `{"description":"npm project","repository":{"type":"git","url":"git+https://github.com/example/npm.git"}}`,
+		"Cargo.toml":     "[package]\nname = \"crate\"\ndescription = \"Rust project\" # comment\nrepository = 'https://github.com/example/crate'\n",
+		"pyproject.toml": "[project]\ndescription = \"Python project\"\n\n[project.urls]\nHomepage = \"https://example.com/python\"\n",
+		"composer.json":  `{"description":"PHP project","homepage":"https://example.com/php"}`,
+		"go.mod":         "module github.com/example/module\n\ngo 1.12\n",
+	}
+	expected := map[string]projectMetadata{
+		"package.json":   {"package.json", "https://github.com/example/npm", "npm project"},
+		"Cargo.toml":     {"Cargo.toml", "https://github.com/example/crate", "Rust project"},
+		"pyproject.toml": {"pyproject.toml", "https://example.com/python", "Python project"},
+		"composer.json":  {"composer.json", "https://example.com/php", "PHP project"},
+		"go.mod":         {"go.mod", "https://github.com/example/module", ""},
+	}
+	for name, content := range files {
+		directory, err := ioutil.TempDir("", "licensezero-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(directory)
+		ioutil.WriteFile(path.Join(directory, name), []byte(content), 0644)
+		inferred := inferProject(directory)
+		if inferred == nil || *inferred != expected[name] {
+			t.Errorf("%s: got %+v", name, inferred)
+		}
+	}
+}
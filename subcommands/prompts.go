@@ -23,14 +23,33 @@ func confirm(prompt string) bool {
 	}
 }
 
+// choose prompts until the response is one of options.
+func choose(prompt string, options []string) string {
+	var response string
+	fmt.Printf("%s (%s): ", prompt, strings.Join(options, "/"))
+	_, err := fmt.Scan(&response)
+	if err != nil {
+		panic(err)
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	for _, option := range options {
+		if response == option {
+			return response
+		}
+	}
+	return choose(prompt, options)
+}
+
+// secretPrompt reads a line without echoing it.
+// The prompt goes to standard error, so it does not mix with output.
 func secretPrompt(prompt string) string {
-	fmt.Printf(prompt)
+	os.Stderr.WriteString(prompt)
 	data, err := terminal.ReadPassword(int(os.Stdin.Fd()))
 	if err != nil {
 		panic(err)
 	}
 	response := string(data)
-	fmt.Println()
+	os.Stderr.WriteString("\n")
 	return response
 }
 
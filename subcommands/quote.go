@@ -0,0 +1,214 @@
+package subcommands
+
+import "encoding/json"
+import "flag"
+import "io/ioutil"
+import "licensezero.com/cli/api"
+import "os"
+import "path/filepath"
+import "strconv"
+import "strings"
+import "text/tabwriter"
+import "time"
+
+const quoteDescription = "Quote private licenses for dependencies."
+
+type quoteItem struct {
+	OfferID     string              `json:"offerID"`
+	Packages    []string            `json:"packages"`
+	Developer   string              `json:"developer,omitempty"`
+	Homepage    string              `json:"homepage,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Pricing     api.Pricing         `json:"pricing"`
+	Lock        api.LockInformation `json:"lock"`
+	Locked      bool                `json:"locked"`
+	Retracted   string              `json:"retracted,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+type quoteOutput struct {
+	Offers     []quoteItem `json:"offers"`
+	Total      uint        `json:"total"`
+	Unresolved []string    `json:"unresolved,omitempty"`
+	Skipped    []string    `json:"skipped,omitempty"`
+}
+
+// Quote lists License Zero offers for npm dependencies,
+// installed or in lockfiles.
+var Quote = &Subcommand{
+	Description: quoteDescription,
+	Handler: func(args []string, paths Paths, client *api.Client) {
+		flagSet := flag.NewFlagSet("quote", flag.ExitOnError)
+		outputJSON := flagSet.Bool("json", false, "")
+		lockfile := flagSet.Bool("lockfile", false, "")
+		tarballs := flagSet.String("tarballs", "", "")
+		flagSet.SetOutput(ioutil.Discard)
+		flagSet.Usage = quoteUsage
+		flagSet.Parse(args)
+		if flagSet.NArg() != 0 {
+			quoteUsage()
+		}
+		applyOutput(flagSet, outputJSON, readSettings(paths))
+		if _, err := os.Stat(filepath.Join(paths.CWD, "node_modules")); os.IsNotExist(err) {
+			*lockfile = true
+		}
+		var dependencies []dependency
+		var unresolved []string
+		var err error
+		if *lockfile {
+			stores := defaultPackageStores(paths.Home, paths.CWD)
+			if *tarballs != "" {
+				stores.tarballs = []string{*tarballs}
+			}
+			dependencies, unresolved, err = scanLockfiles(paths.CWD, stores)
+		} else {
+			dependencies, err = scanNodeModules(paths.CWD)
+		}
+		if err != nil {
+			Fail("Could not read dependencies: " + err.Error() + ".")
+		}
+		output, failed := quoteDependencies(client, dependencies)
+		output.Unresolved = unresolved
+		exitStatus := 0
+		if failed {
+			exitStatus = apiErrorStatus
+		}
+		if len(output.Skipped) != 0 {
+			os.Stderr.WriteString("Skipped invalid License Zero metadata:\n")
+			for _, skipped := range output.Skipped {
+				os.Stderr.WriteString("  " + skipped + "\n")
+			}
+		}
+		if len(unresolved) != 0 {
+			os.Stderr.WriteString("Could not find " + strconv.Itoa(len(unresolved)) + " packages in caches or tarballs:\n")
+			for _, name := range unresolved {
+				os.Stderr.WriteString("  " + name + "\n")
+			}
+		}
+		if *outputJSON {
+			marshalled, err := json.Marshal(output)
+			if err != nil {
+				Fail("Error serializing output.")
+			}
+			os.Stdout.WriteString(string(marshalled) + "\n")
+			os.Exit(exitStatus)
+		}
+		if len(output.Offers) == 0 {
+			os.Stdout.WriteString("No License Zero dependencies found.\n")
+			os.Exit(exitStatus)
+		}
+		table := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		table.Write([]byte("OFFER\tPACKAGES\tDEVELOPER\tPRICE\tSTATUS\n"))
+		for _, item := range output.Offers {
+			price := ""
+			if item.Error == "" {
+				price = currency(item.Pricing.Private)
+			}
+			table.Write([]byte(item.OfferID + "\t" + strings.Join(item.Packages, ", ") + "\t" + item.Developer + "\t" + price + "\t" + quoteStatus(&item) + "\n"))
+		}
+		table.Flush()
+		os.Stdout.WriteString("\nTotal: " + currency(output.Total) + " for " + strconv.Itoa(len(output.Offers)) + " offers\n")
+		os.Exit(exitStatus)
+	},
+}
+
+// quoteDependencies fetches pricing for each offer in dependencies once.
+// The total excludes retracted offers and offers that could not be fetched.
+// Packages with invalid metadata and invalid offer IDs are skipped.
+func quoteDependencies(client *api.Client, dependencies []dependency) (*quoteOutput, bool) {
+	output := quoteOutput{Offers: []quoteItem{}}
+	indices := make(map[string]int)
+	var offers []api.OfferInformation
+	for _, dependency := range dependencies {
+		label := dependency.label()
+		if dependency.Invalid != "" {
+			output.Skipped = append(output.Skipped, label+": "+dependency.Invalid)
+			continue
+		}
+		for _, offer := range dependency.Offers {
+			if !validID(offer.OfferID) {
+				output.Skipped = append(output.Skipped, label+": invalid offer ID "+strconv.Quote(offer.OfferID))
+				continue
+			}
+			index, seen := indices[offer.OfferID]
+			if !seen {
+				index = len(output.Offers)
+				indices[offer.OfferID] = index
+				output.Offers = append(output.Offers, quoteItem{OfferID: offer.OfferID, Packages: []string{}})
+				offers = append(offers, api.OfferInformation{OfferID: offer.OfferID})
+			}
+			item := &output.Offers[index]
+			if !containsString(item.Packages, label) {
+				item.Packages = append(item.Packages, label)
+			}
+		}
+	}
+	failed := false
+	for i, result := range fetchOfferings(client, offers) {
+		item := &output.Offers[i]
+		if result.err != nil {
+			item.Error = result.err.Error()
+			failed = true
+			continue
+		}
+		item.Developer = result.info.Developer.Name
+		item.Homepage = result.info.Homepage
+		item.Description = result.info.Description
+		item.Pricing = result.info.Pricing
+		item.Lock = result.info.Lock
+		item.Locked = lockActive(&item.Lock, time.Now())
+		item.Retracted = result.info.Retracted
+		if item.Retracted == "" {
+			output.Total += item.Pricing.Private
+		}
+	}
+	return &output, failed
+}
+
+func quoteStatus(item *quoteItem) string {
+	if item.Error != "" {
+		return "error: " + item.Error
+	}
+	if item.Retracted != "" {
+		return "retracted " + item.Retracted
+	}
+	if item.Locked {
+		return "locked until " + item.Lock.Unlock
+	}
+	return "available"
+}
+
+// lockActive reports whether a pricing lock has yet to expire.
+func lockActive(lock *api.LockInformation, now time.Time) bool {
+	if lock.Unlock == "" {
+		return false
+	}
+	unlock, err := time.Parse(time.RFC3339, lock.Unlock)
+	return err == nil && unlock.After(now)
+}
+
+func containsString(list []string, value string) bool {
+	for _, element := range list {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteUsage() {
+	usage := quoteDescription + "\n\n" +
+		"Usage:\n" +
+		"  licensezero quote [--json] [--lockfile [--tarballs DIR]]\n\n" +
+		"Options:\n" +
+		flagsList(map[string]string{
+			"json":         "Output JSON.",
+			"lockfile":     "Read package-lock.json, yarn.lock, and pnpm-lock.yaml instead of node_modules.",
+			"tarballs DIR": "Directory of package tarballs to search with --lockfile.",
+		}) + "\n" +
+		"Scans node_modules, including workspaces', for License Zero metadata.\n" +
+		"Without node_modules, or with --lockfile, reads packages from the npm,\n" +
+		"pnpm, and Yarn 1 caches, tarballs, and local file: tarballs in lockfiles.\n" +
+		"The total excludes retracted offers.\n"
+	Fail(usage)
+}
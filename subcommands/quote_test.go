@@ -0,0 +1,21 @@
+package subcommands
+
+import "licensezero.com/cli/api"
+import "testing"
+import "time"
+
+func TestLockActive(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := map[string]bool{
+		"":                     false,
+		"2019-12-31T00:00:00Z": false,
+		"2020-01-02T00:00:00Z": true,
+		"invalid":              false,
+	}
+	for unlock, expected := range cases {
+		lock := api.LockInformation{Locked: "2019-01-01T00:00:00Z", Unlock: unlock, Price: 100}
+		if lockActive(&lock, now) != expected {
+			t.Errorf("%q: expected %v", unlock, expected)
+		}
+	}
+}
@@ -1,8 +1,8 @@
 package subcommands
 
+import "context"
 import "flag"
 import "licensezero.com/cli/api"
-import "licensezero.com/cli/data"
 import "io/ioutil"
 import "os"
 
@@ -12,7 +12,7 @@ const commissionLine = "Agent's commission (percent)."
 // Raise changes pricing.
 var Raise = &Subcommand{
 	Description: raiseDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		flagSet := flag.NewFlagSet("raise", flag.ExitOnError)
 		commission := flagSet.Uint("commission", 0, commissionLine)
 		id := idFlag(flagSet)
@@ -26,16 +26,10 @@ var Raise = &Subcommand{
 		if !validID(*id) {
 			invalidID()
 		}
-		developer, err := data.ReadDeveloper(paths.Home)
+		developer := readDeveloper(paths)
+		err := client.Raise(context.Background(), developer, *id, *commission)
 		if err != nil {
-			Fail(developerHint)
-		}
-		if err != nil {
-			Fail(err.Error())
-		}
-		err = api.Raise(developer, *id, *commission)
-		if err != nil {
-			Fail("Error sending raise request:" + err.Error())
+			failAPI("Error sending raise request", err)
 		}
 		if !*silent {
 			os.Stdout.WriteString("Done.\n")
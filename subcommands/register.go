@@ -1,5 +1,6 @@
 package subcommands
 
+import "context"
 import "licensezero.com/cli/api"
 import "licensezero.com/cli/data"
 import "os"
@@ -9,10 +10,10 @@ const registerDescription = "Register to sell private licenses."
 // Register a user to sell private licenses.
 var Register = &Subcommand{
 	Description: registerDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		identity, err := data.ReadIdentity(paths.Home)
 		if err != nil {
-			Fail(identityHint)
+			failRead(err, identityHint)
 		}
 		os.Stdout.WriteString("Name: " + identity.Name + "\n")
 		os.Stdout.WriteString("Jurisdiction: " + identity.Jurisdiction + "\n")
@@ -24,9 +25,9 @@ var Register = &Subcommand{
 		if !confirmTermsOfService() {
 			Fail(termsHint)
 		}
-		err = api.Register(identity)
+		err = client.Register(context.Background(), identity)
 		if err != nil {
-			Fail("Error sending register request: " + err.Error())
+			failAPI("Error sending register request", err)
 		}
 		os.Stdout.WriteString("Follow the Stripe authorization link sent by e-mail.\n")
 		os.Stdout.WriteString("If you cannot find the e-mail, check your junk mail folder.\n")
@@ -1,8 +1,8 @@
 package subcommands
 
+import "context"
 import "flag"
 import "licensezero.com/cli/api"
-import "licensezero.com/cli/data"
 import "io/ioutil"
 import "os"
 
@@ -11,7 +11,7 @@ const repriceDescription = "Change pricing."
 // Reprice changes pricing.
 var Reprice = &Subcommand{
 	Description: repriceDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		flagSet := flag.NewFlagSet("reprice", flag.ExitOnError)
 		price := priceFlag(flagSet)
 		relicense := relicenseFlag(flagSet)
@@ -22,6 +22,7 @@ var Reprice = &Subcommand{
 		flagSet.SetOutput(ioutil.Discard)
 		flagSet.Usage = repriceUsage
 		flagSet.Parse(args)
+		applyRelicense(flagSet, relicense, readSettings(paths))
 		if *price == 0 || (*offerID == "" && *id == "") {
 			repriceUsage()
 		}
@@ -37,16 +38,10 @@ var Reprice = &Subcommand{
 		if !validID(*id) {
 			invalidID()
 		}
-		developer, err := data.ReadDeveloper(paths.Home)
+		developer := readDeveloper(paths)
+		err := client.Reprice(context.Background(), developer, *id, *price, *relicense)
 		if err != nil {
-			Fail(developerHint)
-		}
-		if err != nil {
-			Fail(err.Error())
-		}
-		err = api.Reprice(developer, *id, *price, *relicense)
-		if err != nil {
-			Fail("Error sending reprice request:" + err.Error())
+			failAPI("Error sending reprice request", err)
 		}
 		if !*silent {
 			os.Stdout.WriteString("Repriced.\n")
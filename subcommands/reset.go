@@ -1,5 +1,6 @@
 package subcommands
 
+import "context"
 import "licensezero.com/cli/api"
 import "licensezero.com/cli/data"
 import "os"
@@ -9,18 +10,15 @@ const resetDescription = "Reset your API access token."
 // Reset requests a new access token.
 var Reset = &Subcommand{
 	Description: resetDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		identity, err := data.ReadIdentity(paths.Home)
 		if err != nil {
-			Fail(identityHint)
+			failRead(err, identityHint)
 		}
-		developer, err := data.ReadDeveloper(paths.Home)
+		developer := readCredentials(paths)
+		err = client.Reset(context.Background(), identity, developer)
 		if err != nil {
-			Fail(developerHint)
-		}
-		err = api.Reset(identity, developer)
-		if err != nil {
-			Fail("Error sending reset request: " + err.Error())
+			failAPI("Error sending reset request", err)
 		}
 		os.Stdout.WriteString("Check your e-mail for the reset link.\n")
 		os.Exit(0)
@@ -0,0 +1,151 @@
+package subcommands
+
+import "encoding/json"
+import "flag"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/data"
+import "io/ioutil"
+import "os"
+import "sort"
+import "strings"
+
+const restoreDescription = "Restore your data from a backup."
+
+// Restore replaces configuration files with those in a backup.
+var Restore = &Subcommand{
+	Description: restoreDescription,
+	Handler: func(args []string, paths Paths, client *api.Client) {
+		flagSet := flag.NewFlagSet("restore", flag.ExitOnError)
+		profile := flagSet.String("profile", "", "")
+		fromProfile := flagSet.String("from-profile", "", "")
+		silent := silentFlag(flagSet)
+		flagSet.SetOutput(ioutil.Discard)
+		flagSet.Usage = restoreUsage
+		flagSet.Parse(args)
+		if flagSet.NArg() < 1 {
+			restoreUsage()
+		}
+		fileName := flagSet.Arg(0)
+		// Allow flags after the file name.
+		flagSet.Parse(flagSet.Args()[1:])
+		if flagSet.NArg() != 0 {
+			restoreUsage()
+		}
+		if *profile != "" && !data.ValidProfileName(*profile) {
+			Fail("Invalid profile name.")
+		}
+		if *fromProfile != "" && !data.ValidProfileName(*fromProfile) {
+			Fail("Invalid profile name.")
+		}
+		if *fromProfile != "" && *profile == "" {
+			*profile = data.ActiveProfile(paths.Home)
+		}
+		content, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			Fail("Could not read " + fileName + ".")
+		}
+		backup, err := data.ReadBackup(content, func() string {
+			return passphrase("Passphrase: ")
+		})
+		if err != nil {
+			Fail("Invalid backup: " + err.Error() + ".")
+		}
+		if *profile != "" && *fromProfile == "" {
+			// Backups made before profiles, or of the default
+			// profile, have files only for the default profile.
+			*fromProfile = *profile
+			if len(backup.ForProfile(*profile).Files) == 0 {
+				*fromProfile = data.DefaultProfile
+			}
+		}
+		if *profile != "" && len(backup.ForProfile(*fromProfile).Files) == 0 {
+			Fail("The backup has no files for profile " + *fromProfile + ".")
+		}
+		if *profile != "" && *fromProfile != *profile && !*silent {
+			os.Stdout.WriteString("Restoring profile " + *profile + " from the backup's " + *fromProfile + " profile.\n")
+		}
+		changes, err := data.PlanRestore(paths.Home, backup, *fromProfile, *profile)
+		if err != nil {
+			Fail("Could not read current configuration: " + err.Error())
+		}
+		if len(changes) == 0 {
+			if !*silent {
+				os.Stdout.WriteString("Your configuration already matches the backup.\n")
+			}
+			os.Exit(0)
+		}
+		os.Stdout.WriteString("Changes:\n")
+		for _, change := range changes {
+			os.Stdout.WriteString(describeChange(change))
+		}
+		if !confirm("Restore?") {
+			os.Exit(0)
+		}
+		saved, err := data.Restore(paths.Home, changes)
+		if err != nil {
+			message := "Could not restore: " + err.Error()
+			if saved != "" {
+				message += "\nYour previous configuration is in " + saved + "."
+			}
+			Fail(message)
+		}
+		if !*silent {
+			os.Stdout.WriteString("Restored. Your previous configuration is in " + saved + ".\n")
+		}
+		os.Exit(0)
+	},
+}
+
+// describeChange summarizes a change to a file, listing changed
+// JSON properties.  It never prints access tokens.
+func describeChange(change data.RestoreChange) string {
+	if change.Current == nil {
+		return "  + " + change.Path + "\n"
+	}
+	if change.Restored == nil {
+		return "  - " + change.Path + "\n"
+	}
+	output := "  ~ " + change.Path + "\n"
+	var current, restored map[string]interface{}
+	if json.Unmarshal(change.Current, &current) != nil || json.Unmarshal(change.Restored, &restored) != nil {
+		return output + "      " + strings.TrimSpace(string(change.Current)) + " -> " + strings.TrimSpace(string(change.Restored)) + "\n"
+	}
+	keys := make(map[string]bool)
+	for key := range current {
+		keys[key] = true
+	}
+	for key := range restored {
+		keys[key] = true
+	}
+	var sorted []string
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	for _, key := range sorted {
+		before, _ := json.Marshal(current[key])
+		after, _ := json.Marshal(restored[key])
+		if string(before) == string(after) {
+			continue
+		}
+		if key == "token" || key == "encryptedToken" {
+			output += "      " + key + ": (changed)\n"
+		} else {
+			output += "      " + key + ": " + string(before) + " -> " + string(after) + "\n"
+		}
+	}
+	return output
+}
+
+func restoreUsage() {
+	usage := restoreDescription + "\n\n" +
+		"Usage:\n" +
+		"  licensezero restore FILE [--profile NAME] [--from-profile NAME]\n\n" +
+		"Options:\n" +
+		flagsList(map[string]string{
+			"from-profile NAME": "Restore the files of this profile in the backup. Defaults to --profile if the backup has it, else the default profile.",
+			"profile NAME":      "Restore only the files of this profile. Defaults to the profile in use with --from-profile.",
+			"silent":            silentLine,
+		})
+	Fail(usage)
+}
@@ -1,8 +1,8 @@
 package subcommands
 
+import "context"
 import "flag"
 import "licensezero.com/cli/api"
-import "licensezero.com/cli/data"
 import "io/ioutil"
 import "os"
 
@@ -11,7 +11,7 @@ const retractDescription = "Stop offering private licenses for sale."
 // Retract pulls an offer from sale.
 var Retract = &Subcommand{
 	Description: retractDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		flagSet := flag.NewFlagSet("retract", flag.ExitOnError)
 		offerID := offerIDFlag(flagSet)
 		id := idFlag(flagSet)
@@ -31,13 +31,10 @@ var Retract = &Subcommand{
 		if !validID(*id) {
 			invalidID()
 		}
-		developer, err := data.ReadDeveloper(paths.Home)
+		developer := readDeveloper(paths)
+		err := client.Retract(context.Background(), developer, *id)
 		if err != nil {
-			Fail(developerHint)
-		}
-		err = api.Retract(developer, *id)
-		if err != nil {
-			Fail("Error sending retract request: " + err.Error())
+			failAPI("Error sending retract request", err)
 		}
 		if !*silent {
 			os.Stdout.WriteString("Retracted from sale.\n")
@@ -0,0 +1,123 @@
+package subcommands
+
+import "errors"
+import "flag"
+import "licensezero.com/cli/data"
+import "strconv"
+
+// setting describes a key in config.json for `licensezero config`.
+type setting struct {
+	description string
+	get         func(*data.Settings) string
+	set         func(*data.Settings, string) error
+	unset       func(*data.Settings)
+}
+
+var settingKeys = map[string]setting{
+	"do-not-open": {
+		description: "Never open pages in a browser: true or false.",
+		get: func(settings *data.Settings) string {
+			if !settings.DoNotOpen {
+				return ""
+			}
+			return "true"
+		},
+		set: func(settings *data.Settings, value string) error {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return errors.New("expected true or false")
+			}
+			settings.DoNotOpen = parsed
+			return nil
+		},
+		unset: func(settings *data.Settings) { settings.DoNotOpen = false },
+	},
+	"relicense": {
+		description: "Default cost to relicense on Charity terms, in US cents.",
+		get: func(settings *data.Settings) string {
+			if settings.Relicense == 0 {
+				return ""
+			}
+			return strconv.FormatUint(uint64(settings.Relicense), 10)
+		},
+		set: func(settings *data.Settings, value string) error {
+			parsed, err := strconv.ParseUint(value, 10, 0)
+			if err != nil || parsed == 0 {
+				return errors.New("expected a positive number of cents")
+			}
+			settings.Relicense = uint(parsed)
+			return nil
+		},
+		unset: func(settings *data.Settings) { settings.Relicense = 0 },
+	},
+	"jurisdiction": {
+		description: "Default user jurisdiction for waivers (ISO 3166-2, like \"US-CA\").",
+		get: func(settings *data.Settings) string {
+			return settings.Jurisdiction
+		},
+		set: func(settings *data.Settings, value string) error {
+			if !validJurisdiction(value) {
+				return errors.New("expected an ISO 3166-2 code, like \"US-CA\"")
+			}
+			settings.Jurisdiction = value
+			return nil
+		},
+		unset: func(settings *data.Settings) { settings.Jurisdiction = "" },
+	},
+	"output": {
+		description: "Output format for commands that support --json: text or json.",
+		get: func(settings *data.Settings) string {
+			return settings.Output
+		},
+		set: func(settings *data.Settings, value string) error {
+			if value != "text" && value != "json" {
+				return errors.New("expected text or json")
+			}
+			settings.Output = value
+			return nil
+		},
+		unset: func(settings *data.Settings) { settings.Output = "" },
+	},
+}
+
+// readSettings reads config.json, failing if it is malformed.
+func readSettings(paths Paths) *data.Settings {
+	settings, err := data.ReadSettings(paths.Home)
+	if err != nil {
+		failRead(err, "Could not read config.json.")
+	}
+	return settings
+}
+
+// flagPassed reports whether a flag was given on the command line.
+func flagPassed(flagSet *flag.FlagSet, name string) bool {
+	passed := false
+	flagSet.Visit(func(given *flag.Flag) {
+		if given.Name == name {
+			passed = true
+		}
+	})
+	return passed
+}
+
+// applyDoNotOpen defaults --do-not-open from config.json.
+func applyDoNotOpen(flagSet *flag.FlagSet, doNotOpen *bool, settings *data.Settings) {
+	if !flagPassed(flagSet, "do-not-open") {
+		*doNotOpen = settings.DoNotOpen
+	}
+}
+
+// applyRelicense defaults --relicense from config.json,
+// unless --relicense or --no-relicense was given.
+func applyRelicense(flagSet *flag.FlagSet, relicense *uint, settings *data.Settings) {
+	if !flagPassed(flagSet, "relicense") && !flagPassed(flagSet, "no-relicense") {
+		*relicense = settings.Relicense
+	}
+}
+
+// applyOutput defaults --json from config.json.
+func applyOutput(flagSet *flag.FlagSet, outputJSON *bool, settings *data.Settings) {
+	if !flagPassed(flagSet, "json") {
+		*outputJSON = settings.Output == "json"
+	}
+}
@@ -0,0 +1,88 @@
+package subcommands
+
+import "context"
+import "flag"
+import "io/ioutil"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/data"
+import "os"
+
+const stampDescription = "Record an offer in project metadata."
+
+// Stamp writes offer metadata to package.json or .licensezero.json.
+var Stamp = &Subcommand{
+	Description: stampDescription,
+	Handler: func(args []string, paths Paths, client *api.Client) {
+		flagSet := flag.NewFlagSet("stamp", flag.ExitOnError)
+		id := idFlag(flagSet)
+		silent := silentFlag(flagSet)
+		flagSet.SetOutput(ioutil.Discard)
+		flagSet.Usage = stampUsage
+		flagSet.Parse(args)
+		if *id == "" || flagSet.NArg() != 0 {
+			stampUsage()
+		}
+		if !validID(*id) {
+			invalidID()
+		}
+		stampOffer(paths, client, *id, *silent)
+		os.Exit(0)
+	},
+}
+
+// stampOffer fetches an offer's details and records them in the
+// project in the current directory, failing if it cannot.
+func stampOffer(paths Paths, client *api.Client, offerID string, silent bool) {
+	information, err := client.Offering(context.Background(), offerID)
+	if err != nil {
+		failAPI("Could not fetch offer information", err)
+	}
+	if information.Retracted != "" {
+		Fail("Offer " + offerID + " has been retracted.")
+	}
+	_, err = data.ParsePublicKey(information.Developer.PublicKey)
+	if err != nil {
+		Fail("Invalid developer public key from API.")
+	}
+	offer := data.ProjectOffer{
+		OfferID: offerID,
+		Developer: data.Party{
+			Name:         information.Developer.Name,
+			Jurisdiction: information.Developer.Jurisdiction,
+			PublicKey:    information.Developer.PublicKey,
+		},
+		Homepage:    information.Homepage,
+		Description: information.Description,
+		Pricing: data.ProjectPricing{
+			Private:   information.Pricing.Private,
+			Relicense: information.Pricing.Relicense,
+		},
+	}
+	written, err := data.StampProject(paths.CWD, &offer)
+	if invalid, ok := err.(*data.InvalidMetadataError); ok {
+		Fail("Could not read project metadata: " + invalid.Error() + ".")
+	}
+	if malformed, ok := err.(*data.MalformedFileError); ok {
+		Fail("Could not read project metadata: " + malformed.Error() + ".")
+	}
+	if err != nil {
+		Fail("Could not write project metadata.")
+	}
+	if !silent {
+		os.Stdout.WriteString("Stamped " + offerID + " in " + written + ".\n")
+	}
+}
+
+func stampUsage() {
+	usage := stampDescription + "\n\n" +
+		"Usage:\n" +
+		"  licensezero stamp --id ID\n\n" +
+		"Options:\n" +
+		flagsList(map[string]string{
+			"id ID":  idLine,
+			"silent": silentLine,
+		}) + "\n" +
+		"Writes to the licensezero array in package.json, if there is one,\n" +
+		"or to " + data.ProjectMetadataFile + " otherwise.\n"
+	Fail(usage)
+}
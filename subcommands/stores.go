@@ -0,0 +1,216 @@
+package subcommands
+
+import "archive/tar"
+import "bytes"
+import "compress/gzip"
+import "crypto/sha512"
+import "encoding/base64"
+import "encoding/hex"
+import "encoding/json"
+import "errors"
+import "io"
+import "io/ioutil"
+import "os"
+import "path"
+import "path/filepath"
+import "strings"
+
+// packageStores locates the package.json of packages that are not
+// installed, in package manager caches and directories of tarballs.
+type packageStores struct {
+	root      string
+	tarballs  []string
+	npmCaches []string
+	pnpm      []string
+	yarn      []string
+}
+
+// defaultPackageStores finds the caches of npm, pnpm, and Yarn 1
+// in their default locations or the locations set in the environment.
+func defaultPackageStores(home, root string) *packageStores {
+	stores := packageStores{root: root}
+	npmCache := os.Getenv("npm_config_cache")
+	if npmCache == "" {
+		npmCache = filepath.Join(home, ".npm")
+	}
+	stores.npmCaches = []string{filepath.Join(npmCache, "_cacache")}
+	dataHome := environmentPath("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	stores.pnpm = []string{
+		filepath.Join(dataHome, "pnpm", "store", "v3"),
+		filepath.Join(home, "Library", "pnpm", "store", "v3"),
+	}
+	if yarnCache := os.Getenv("YARN_CACHE_FOLDER"); yarnCache != "" {
+		stores.yarn = []string{yarnCache}
+	} else {
+		cacheHome := environmentPath("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+		stores.yarn = []string{
+			filepath.Join(cacheHome, "yarn", "v6"),
+			filepath.Join(home, "Library", "Caches", "Yarn", "v6"),
+		}
+	}
+	return &stores
+}
+
+func environmentPath(variable, fallback string) string {
+	value := os.Getenv(variable)
+	if filepath.IsAbs(value) {
+		return value
+	}
+	return fallback
+}
+
+var errPackageNotFound = errors.New("package not found")
+
+// find returns the package.json of a locked package and where it was found.
+func (stores *packageStores) find(locked *lockedPackage) ([]byte, string, error) {
+	for _, tarball := range stores.tarballPaths(locked) {
+		content, err := readTarball(tarball, locked.Integrity)
+		if err == nil {
+			return content, tarball, nil
+		}
+	}
+	algorithm, digest := parseIntegrity(locked.Integrity)
+	if digest != "" {
+		for _, cache := range stores.npmCaches {
+			file := filepath.Join(cache, "content-v2", algorithm, digest[:2], digest[2:4], digest[4:])
+			content, err := readTarball(file, "")
+			if err == nil {
+				return content, file, nil
+			}
+		}
+	}
+	if algorithm == "sha512" {
+		for _, store := range stores.pnpm {
+			content, err := readPnpmPackageJSON(store, digest)
+			if err == nil {
+				return content, store, nil
+			}
+		}
+	}
+	for _, cache := range stores.yarn {
+		pattern := "npm-" + strings.Replace(locked.Name, "/", "-", -1) + "-" + locked.Version + "-*"
+		matches, _ := filepath.Glob(filepath.Join(cache, pattern))
+		for _, match := range matches {
+			file := filepath.Join(match, "node_modules", filepath.FromSlash(locked.Name), "package.json")
+			content, err := ioutil.ReadFile(file)
+			if err == nil {
+				return content, match, nil
+			}
+		}
+	}
+	return nil, "", errPackageNotFound
+}
+
+// tarballPaths lists where a package's tarball might be: local paths
+// in the lockfile, and names npm pack, Yarn offline mirrors, and
+// registry URLs use, in tarball directories.
+func (stores *packageStores) tarballPaths(locked *lockedPackage) []string {
+	var paths []string
+	resolved := strings.Split(locked.Resolved, "#")[0]
+	if strings.HasPrefix(resolved, "file:") {
+		local := filepath.FromSlash(strings.TrimPrefix(resolved, "file:"))
+		if !filepath.IsAbs(local) {
+			local = filepath.Join(stores.root, local)
+		}
+		paths = append(paths, local)
+	}
+	if len(stores.tarballs) == 0 || locked.Version == "" {
+		return paths
+	}
+	unscoped := locked.Name[strings.LastIndex(locked.Name, "/")+1:]
+	names := []string{
+		strings.Replace(strings.TrimPrefix(locked.Name, "@"), "/", "-", -1) + "-" + locked.Version + ".tgz",
+		strings.Replace(locked.Name, "/", "-", -1) + "-" + locked.Version + ".tgz",
+		unscoped + "-" + locked.Version + ".tgz",
+	}
+	if strings.HasPrefix(resolved, "http") {
+		names = append([]string{path.Base(resolved)}, names...)
+	}
+	for _, directory := range stores.tarballs {
+		for _, name := range names {
+			paths = append(paths, filepath.Join(directory, name))
+		}
+	}
+	return paths
+}
+
+// parseIntegrity returns the algorithm and hex digest of the strongest
+// hash in a subresource integrity string.
+func parseIntegrity(integrity string) (string, string) {
+	var algorithm, digest string
+	for _, hash := range strings.Fields(integrity) {
+		parts := strings.SplitN(hash, "-", 2)
+		if len(parts) != 2 || (parts[0] != "sha512" && parts[0] != "sha1") {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(strings.Split(parts[1], "?")[0])
+		if err != nil || len(decoded) < 3 {
+			continue
+		}
+		if algorithm != "sha512" {
+			algorithm, digest = parts[0], hex.EncodeToString(decoded)
+		}
+	}
+	return algorithm, digest
+}
+
+// readTarball reads package.json from a package tarball,
+// checking the tarball against integrity if it has a SHA-512 hash.
+func readTarball(file, integrity string) ([]byte, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if algorithm, digest := parseIntegrity(integrity); algorithm == "sha512" {
+		sum := sha512.Sum512(content)
+		if hex.EncodeToString(sum[:]) != digest {
+			return nil, errors.New(file + " does not match integrity")
+		}
+	}
+	decompressed, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	archive := tar.NewReader(decompressed)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil, errors.New(file + " has no package.json")
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Tarballs usually put files under package/, but not always.
+		parts := strings.SplitN(strings.TrimPrefix(header.Name, "./"), "/", 2)
+		if len(parts) == 2 && parts[1] == "package.json" {
+			return ioutil.ReadAll(archive)
+		}
+	}
+}
+
+// readPnpmPackageJSON reads package.json from pnpm's
+// content-addressable store, by the package's SHA-512 digest.
+func readPnpmPackageJSON(store, digest string) ([]byte, error) {
+	var index struct {
+		Files map[string]struct {
+			Integrity string `json:"integrity"`
+		} `json:"files"`
+	}
+	content, err := ioutil.ReadFile(filepath.Join(store, "files", digest[:2], digest[2:]+"-index.json"))
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(content, &index)
+	if err != nil {
+		return nil, err
+	}
+	file, ok := index.Files["package.json"]
+	if !ok {
+		return nil, errPackageNotFound
+	}
+	algorithm, fileDigest := parseIntegrity(file.Integrity)
+	if algorithm != "sha512" {
+		return nil, errPackageNotFound
+	}
+	return ioutil.ReadFile(filepath.Join(store, "files", fileDigest[:2], fileDigest[2:]))
+}
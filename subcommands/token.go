@@ -1,6 +1,7 @@
 package subcommands
 
 import "flag"
+import "licensezero.com/cli/api"
 import "licensezero.com/cli/data"
 import "io/ioutil"
 import "os"
@@ -10,25 +11,47 @@ const tokenDescription = "Save your API access token."
 // Token saves developer IDs and API tokens.
 var Token = &Subcommand{
 	Description: tokenDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		flagSet := flag.NewFlagSet("token", flag.ExitOnError)
 		developerID := flagSet.String("developer", "", "Developer ID")
+		encrypt := flagSet.Bool("encrypt", false, encryptLine)
 		silent := silentFlag(flagSet)
 		flagSet.SetOutput(ioutil.Discard)
 		flagSet.Usage = tokenUsage
 		flagSet.Parse(args)
-		if *developerID == "" {
+		if *developerID == "" && !*encrypt {
 			tokenUsage()
 		}
-		token := secretPrompt("Token: ")
-		newDeveloper := data.Developer{
-			DeveloperID: *developerID,
-			Token:       token,
+		var newDeveloper data.Developer
+		if *developerID == "" {
+			// Encrypt the token already saved.  Never read credentials
+			// from the environment, which would save them to disk.
+			saved, err := data.ReadDeveloper(paths.Home)
+			if os.IsNotExist(err) {
+				Fail("No saved access token to encrypt. Save one with `licensezero token --developer ID`.")
+			}
+			if err != nil {
+				failRead(err, "Could not read developer file.")
+			}
+			unlockDeveloper(saved)
+			newDeveloper = *saved
+		} else {
+			token := secretPrompt("Token: ")
+			newDeveloper = data.Developer{
+				DeveloperID: *developerID,
+				Token:       token,
+			}
+			existingDeveloper, _ := data.ReadDeveloper(paths.Home)
+			if existingDeveloper != nil && (existingDeveloper.DeveloperID != newDeveloper.DeveloperID || existingDeveloper.Token != newDeveloper.Token) {
+				if !confirm("Overwrite existing developer info?") {
+					os.Exit(0)
+				}
+			}
 		}
-		existingDeveloper, _ := data.ReadDeveloper(paths.Home)
-		if existingDeveloper != nil && *existingDeveloper != newDeveloper {
-			if !confirm("Overwrite existing developer info?") {
-				os.Exit(0)
+		if *encrypt {
+			err := newDeveloper.Encrypt(newPassphrase())
+			if err != nil {
+				Fail("Could not encrypt access token.")
 			}
 		}
 		err := data.WriteDeveloper(paths.Home, &newDeveloper)
@@ -36,7 +59,11 @@ var Token = &Subcommand{
 			Fail("Could not write developer file.")
 		}
 		if !*silent {
-			os.Stdout.WriteString("Saved your developer ID and access token.\n")
+			if *encrypt {
+				os.Stdout.WriteString("Saved your developer ID and encrypted access token.\n")
+			} else {
+				os.Stdout.WriteString("Saved your developer ID and access token.\n")
+			}
 		}
 		os.Exit(0)
 	},
@@ -45,10 +72,12 @@ var Token = &Subcommand{
 func tokenUsage() {
 	usage := tokenDescription + "\n\n" +
 		"Usage:\n" +
-		"  licensezero token --developer ID\n\n" +
+		"  licensezero token --developer ID [--encrypt]\n" +
+		"  licensezero token --encrypt\n\n" +
 		"Options:\n" +
 		flagsList(map[string]string{
 			"developer ID": "Developer ID (UUID).",
+			"encrypt":      encryptLine,
 			"silent":       silentLine,
 		})
 	Fail(usage)
@@ -1,5 +1,7 @@
 package subcommands
 
+import "licensezero.com/cli/api"
+
 // Paths describes the paths in which the CLI is run.
 type Paths struct {
 	Home string
@@ -9,5 +11,5 @@ type Paths struct {
 // Subcommand describes a CLI subcommand.
 type Subcommand struct {
 	Description string
-	Handler     func([]string, Paths)
+	Handler     func([]string, Paths, *api.Client)
 }
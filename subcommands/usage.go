@@ -4,6 +4,14 @@ import "strings"
 import "sort"
 
 func flagsList(mapping map[string]string) string {
+	return alignedList("--", mapping)
+}
+
+func settingsList(mapping map[string]string) string {
+	return alignedList("", mapping)
+}
+
+func alignedList(prefix string, mapping map[string]string) string {
 	returned := ""
 	var flags []string
 	var longest int
@@ -17,7 +25,7 @@ func flagsList(mapping map[string]string) string {
 	sort.Strings(flags)
 	for _, key := range flags {
 		returned = returned +
-			"  --" + key + strings.Repeat(" ", longest-len(key)) +
+			"  " + prefix + key + strings.Repeat(" ", longest-len(key)) +
 			mapping[key] + "\n"
 	}
 	return returned
@@ -0,0 +1,193 @@
+package subcommands
+
+import "encoding/json"
+import "errors"
+import "flag"
+import "golang.org/x/crypto/ed25519"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/data"
+import "io/ioutil"
+import "os"
+import "strconv"
+import "time"
+
+const verifyDescription = "Verify a license or waiver file."
+
+type verification struct {
+	File     string      `json:"file"`
+	Form     string      `json:"form"`
+	Valid    bool        `json:"valid"`
+	Expired  bool        `json:"expired"`
+	OfferID  string      `json:"offerID"`
+	Licensor string      `json:"licensor"`
+	Holder   string      `json:"holder"`
+	Date     string      `json:"date"`
+	Term     interface{} `json:"term,omitempty"`
+	Expires  string      `json:"expires,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// Verify checks the signatures and term of a license or waiver.
+var Verify = &Subcommand{
+	Description: verifyDescription,
+	Handler: func(args []string, paths Paths, client *api.Client) {
+		flagSet := flag.NewFlagSet("verify", flag.ExitOnError)
+		outputJSON := flagSet.Bool("json", false, "")
+		licensorKeyFlag := flagSet.String("licensor-key", "", "")
+		agentKeyFlag := flagSet.String("agent-key", "", "")
+		flagSet.SetOutput(ioutil.Discard)
+		flagSet.Usage = verifyUsage
+		flagSet.Parse(args)
+		if flagSet.NArg() < 1 {
+			verifyUsage()
+		}
+		file := flagSet.Arg(0)
+		// Allow flags after the file name.
+		flagSet.Parse(flagSet.Args()[1:])
+		if flagSet.NArg() != 0 {
+			verifyUsage()
+		}
+		applyOutput(flagSet, outputJSON, readSettings(paths))
+		contents, err := ioutil.ReadFile(file)
+		if err != nil {
+			Fail("Could not read " + file + ".")
+		}
+		result := verification{File: file}
+		// With --json, report malformed documents as invalid, too.
+		malformed := func(message string, err error) {
+			if *outputJSON {
+				result.Error = err.Error()
+				printVerification(&result)
+				os.Exit(invalidDocumentStatus)
+			}
+			Fail(message + err.Error())
+		}
+		form, err := data.DocumentForm(contents)
+		if err != nil {
+			malformed("Not a license or waiver: ", err)
+		}
+		result.Form = form
+		var verifySignatures func(licensorKey, agentKey ed25519.PublicKey) error
+		switch form {
+		case data.WaiverForm:
+			waiver, err := data.ParseWaiver(contents)
+			if err != nil {
+				malformed("Invalid waiver: ", err)
+			}
+			manifest, err := waiver.ParseManifest()
+			if err != nil {
+				malformed("Invalid waiver: ", err)
+			}
+			result.OfferID = manifest.Offer.OfferID
+			result.Licensor = manifest.Licensor.Name
+			result.Holder = manifest.Beneficiary.Name
+			result.Date = manifest.Date
+			result.Term = manifest.Term
+			expires, forever, err := manifest.Expiration()
+			if err != nil {
+				result.Error = err.Error()
+			} else if !forever {
+				result.Expires = expires.Format(time.RFC3339)
+				result.Expired = expires.Before(time.Now())
+			}
+			verifySignatures = waiver.Verify
+		case data.LicenseForm:
+			license, err := data.ParseLicense(contents)
+			if err != nil {
+				malformed("Invalid license: ", err)
+			}
+			manifest, err := license.ParseManifest()
+			if err != nil {
+				malformed("Invalid license: ", err)
+			}
+			result.OfferID = manifest.Offer.OfferID
+			result.Licensor = manifest.Licensor.Name
+			result.Holder = manifest.Licensee.Name
+			result.Date = manifest.Date
+			verifySignatures = license.Verify
+		default:
+			malformed("Not a license or waiver: ", errors.New("unknown form "+form))
+		}
+		if result.Error == "" {
+			date, err := time.Parse(time.RFC3339, result.Date)
+			if err != nil {
+				result.Error = "invalid date"
+			} else if date.After(time.Now().Add(time.Hour)) {
+				result.Error = "dated in the future"
+			}
+		}
+		if result.Error == "" {
+			var licensorKey ed25519.PublicKey
+			if *licensorKeyFlag != "" {
+				licensorKey = pinnedKey(*licensorKeyFlag, "licensor-key")
+			} else {
+				licensorKey = fetchOfferKey(client, result.OfferID)
+			}
+			err = verifySignatures(licensorKey, agentKey(*agentKeyFlag))
+			if err != nil {
+				result.Error = err.Error()
+			}
+		}
+		result.Valid = result.Error == "" && !result.Expired
+		exitStatus := 0
+		if result.Error != "" {
+			exitStatus = invalidDocumentStatus
+		} else if result.Expired {
+			exitStatus = expiredStatus
+		}
+		if *outputJSON {
+			printVerification(&result)
+			os.Exit(exitStatus)
+		}
+		os.Stdout.WriteString("Form:     " + result.Form + "\n")
+		os.Stdout.WriteString("Offer ID: " + result.OfferID + "\n")
+		os.Stdout.WriteString("Licensor: " + result.Licensor + "\n")
+		os.Stdout.WriteString("Holder:   " + result.Holder + "\n")
+		os.Stdout.WriteString("Date:     " + result.Date + "\n")
+		if result.Term != nil {
+			os.Stdout.WriteString("Term:     " + formatTerm(result.Term) + "\n")
+		}
+		if result.Expires != "" {
+			os.Stdout.WriteString("Expires:  " + result.Expires + "\n")
+		}
+		if result.Error != "" {
+			os.Stdout.WriteString("Verdict:  INVALID (" + result.Error + ")\n")
+		} else if result.Expired {
+			os.Stdout.WriteString("Verdict:  EXPIRED\n")
+		} else {
+			os.Stdout.WriteString("Verdict:  Valid\n")
+		}
+		os.Exit(exitStatus)
+	},
+}
+
+func printVerification(result *verification) {
+	marshalled, err := json.Marshal(result)
+	if err != nil {
+		Fail("Error serializing output.")
+	}
+	os.Stdout.WriteString(string(marshalled) + "\n")
+}
+
+func formatTerm(term interface{}) string {
+	if days, ok := term.(float64); ok {
+		return strconv.Itoa(int(days)) + " days"
+	}
+	if text, ok := term.(string); ok {
+		return text
+	}
+	return "unknown"
+}
+
+func verifyUsage() {
+	usage := verifyDescription + "\n\n" +
+		"Usage:\n" +
+		"  licensezero verify FILE\n\n" +
+		"Options:\n" +
+		flagsList(map[string]string{
+			"agent-key HEX":    agentKeyLine,
+			"json":             "Output JSON.",
+			"licensor-key HEX": "Use this licensor public key instead of fetching it.",
+		})
+	Fail(usage)
+}
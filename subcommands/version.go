@@ -1,13 +1,14 @@
 package subcommands
 
 import "os"
+import "licensezero.com/cli/api"
 
 const versionDescription = "Print version."
 
 // Version prints the CLI version.
 var Version = &Subcommand{
 	Description: versionDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		if args[0] == "" {
 			os.Stdout.WriteString("Development Build\n")
 		} else {
@@ -0,0 +1,173 @@
+package subcommands
+
+import "encoding/json"
+import "flag"
+import "io/ioutil"
+import "licensezero.com/cli/api"
+import "licensezero.com/cli/data"
+import "os"
+import "strconv"
+import "strings"
+import "time"
+
+const waiversDescription = "List waivers you have issued."
+
+// Waivers lists and shows archived waivers.
+var Waivers = &Subcommand{
+	Description: waiversDescription,
+	Handler: func(args []string, paths Paths, client *api.Client) {
+		if len(args) > 0 && args[0] == "show" {
+			showWaiver(args[1:], paths)
+		}
+		flagSet := flag.NewFlagSet("waivers", flag.ExitOnError)
+		offerID := offerIDFlag(flagSet)
+		id := idFlag(flagSet)
+		recipient := flagSet.String("recipient", "", "")
+		expired := flagSet.Bool("expired", false, "")
+		active := flagSet.Bool("active", false, "")
+		expiring := flagSet.Uint("expiring", 0, "")
+		outputJSON := flagSet.Bool("json", false, "")
+		flagSet.SetOutput(ioutil.Discard)
+		flagSet.Usage = waiversUsage
+		flagSet.Parse(args)
+		if flagSet.NArg() != 0 || (*offerID != "" && *id != "") || (*expired && (*active || *expiring != 0)) {
+			waiversUsage()
+		}
+		if *offerID != "" {
+			*id = *offerID
+		}
+		applyOutput(flagSet, outputJSON, readSettings(paths))
+		waivers, err := data.ReadWaivers(paths.Home)
+		if err != nil {
+			failRead(err, "Could not read waivers.")
+		}
+		now := time.Now()
+		var horizon time.Time
+		if *expiring != 0 {
+			horizon = now.AddDate(0, 0, int(*expiring))
+		}
+		var items []waiverItem
+		for _, archived := range waivers {
+			item := newWaiverItem(&archived, now)
+			if *id != "" && item.OfferID != *id {
+				continue
+			}
+			if *recipient != "" && !item.matchesRecipient(*recipient) {
+				continue
+			}
+			if *expired && !item.Expired {
+				continue
+			}
+			if (*active || *expiring != 0) && item.Expired {
+				continue
+			}
+			if *expiring != 0 && (item.Expires == "" || !item.expires.Before(horizon)) {
+				continue
+			}
+			items = append(items, item)
+		}
+		if *expiring != 0 && len(items) > 0 {
+			os.Stderr.WriteString("Warning: " + strconv.Itoa(len(items)) + " waiver(s) expire in the next " + strconv.Itoa(int(*expiring)) + " days.\n")
+		}
+		if *outputJSON {
+			if items == nil {
+				items = []waiverItem{}
+			}
+			marshalled, err := json.Marshal(items)
+			if err != nil {
+				Fail("Error serializing output.")
+			}
+			os.Stdout.WriteString(string(marshalled) + "\n")
+			os.Exit(0)
+		}
+		for i, item := range items {
+			if i != 0 {
+				os.Stdout.WriteString("\n")
+			}
+			os.Stdout.WriteString("- ID:        " + item.ID[:12] + "\n")
+			os.Stdout.WriteString("  Offer ID:  " + item.OfferID + "\n")
+			os.Stdout.WriteString("  Recipient: " + item.Name + " <" + item.EMail + "> (" + item.Jurisdiction + ")\n")
+			os.Stdout.WriteString("  Issued:    " + item.Issued + "\n")
+			if item.Expires == "" {
+				os.Stdout.WriteString("  Expires:   Never\n")
+			} else if item.Expired {
+				os.Stdout.WriteString("  Expired:   " + item.Expires + "\n")
+			} else {
+				os.Stdout.WriteString("  Expires:   " + item.Expires + "\n")
+			}
+		}
+		os.Exit(0)
+	},
+}
+
+type waiverItem struct {
+	ID           string `json:"id"`
+	OfferID      string `json:"offerID"`
+	Name         string `json:"name"`
+	Jurisdiction string `json:"jurisdiction"`
+	EMail        string `json:"email"`
+	Issued       string `json:"issued"`
+	Expires      string `json:"expires,omitempty"`
+	Expired      bool   `json:"expired"`
+	expires      time.Time
+}
+
+func newWaiverItem(archived *data.ArchivedWaiver, now time.Time) waiverItem {
+	manifest := archived.Manifest
+	item := waiverItem{
+		ID:           archived.ID,
+		OfferID:      manifest.Offer.OfferID,
+		Name:         manifest.Beneficiary.Name,
+		Jurisdiction: manifest.Beneficiary.Jurisdiction,
+		EMail:        manifest.Beneficiary.EMail,
+		Issued:       manifest.Date,
+	}
+	expires, forever, err := manifest.Expiration()
+	if err == nil && !forever {
+		item.expires = expires
+		item.Expires = expires.UTC().Format(time.RFC3339)
+		item.Expired = !now.Before(expires)
+	}
+	return item
+}
+
+func (item *waiverItem) matchesRecipient(search string) bool {
+	search = strings.ToLower(search)
+	return strings.Contains(strings.ToLower(item.Name), search) ||
+		strings.Contains(strings.ToLower(item.EMail), search)
+}
+
+func showWaiver(args []string, paths Paths) {
+	if len(args) != 1 {
+		waiversUsage()
+	}
+	archived, err := data.ReadWaiver(paths.Home, args[0])
+	if os.IsNotExist(err) {
+		Fail("No waiver with ID " + args[0] + ".")
+	} else if err == data.ErrAmbiguousWaiverID {
+		Fail("More than one waiver has an ID starting with " + args[0] + ".")
+	} else if err != nil {
+		failRead(err, "Could not read waivers.")
+	}
+	os.Stdout.Write(archived.Raw)
+	os.Stdout.WriteString("\n")
+	os.Exit(0)
+}
+
+func waiversUsage() {
+	usage := waiversDescription + "\n\n" +
+		"Usage:\n" +
+		"  licensezero waivers [--id ID] [--recipient TEXT] [--active | --expired | --expiring DAYS] [--json]\n" +
+		"  licensezero waivers show WAIVER\n\n" +
+		"Options:\n" +
+		flagsList(map[string]string{
+			"active":         "List only waivers that have not expired.",
+			"expired":        "List only waivers that have expired.",
+			"expiring DAYS":  "List only waivers that expire in the next DAYS days, with a warning.",
+			"id ID":          "List only waivers for an offer.",
+			"json":           "Output JSON.",
+			"recipient TEXT": "List only waivers for recipients whose names or e-mail addresses contain TEXT.",
+		}) + "\n" +
+		"WAIVER is an ID from `licensezero waivers`, or the start of one.\n"
+	Fail(usage)
+}
@@ -1,6 +1,7 @@
 package subcommands
 
 import "fmt"
+import "licensezero.com/cli/api"
 import "licensezero.com/cli/data"
 import "os"
 
@@ -9,17 +10,24 @@ const whoAmIDescription = "Show your identity information."
 // WhoAmI prints identity information.
 var WhoAmI = &Subcommand{
 	Description: whoAmIDescription,
-	Handler: func(args []string, paths Paths) {
+	Handler: func(args []string, paths Paths, client *api.Client) {
 		identity, err := data.ReadIdentity(paths.Home)
 		if err != nil {
-			Fail("Could not read identity file.")
+			failRead(err, "Could not read identity file.")
 		}
-		developer, err := data.ReadDeveloper(paths.Home)
+		developer, source, err := data.ReadCredentials(paths.Home)
+		if _, malformed := err.(*data.MalformedFileError); malformed {
+			failRead(err, "")
+		} else if err != nil && !os.IsNotExist(err) {
+			Fail("Could not read credentials: " + err.Error() + ".")
+		}
+		fmt.Println("Profile: " + data.ActiveProfile(paths.Home))
 		fmt.Println("Name: " + identity.Name)
 		fmt.Println("Jurisdiction: " + identity.Jurisdiction)
 		fmt.Println("E-Mail: " + identity.EMail)
 		if err == nil {
 			fmt.Println("Developer ID: " + developer.DeveloperID)
+			fmt.Println("Credentials: " + source)
 		}
 		os.Exit(0)
 	},